@@ -0,0 +1,62 @@
+package ttl_cache
+
+import "time"
+
+// Config is a snapshot of a cache's effective configuration: the options it was created with, with defaults
+// filled in for any it was not given. Size reflects the latest Resize. TopKTracking is 0 when top-K tracking is
+// off, and SweepPeriod is 0 under WithNoSweeper.
+type Config struct {
+	Size            uint
+	DefaultTTL      time.Duration
+	SweepPeriod     time.Duration
+	NoSweeper       bool
+	EvictionPolicy  EvictionPolicy
+	MaxKeyLength    int
+	TopKTracking    int
+	AdaptiveTTL     bool
+	SkipEqualWrites bool
+	SelfHealing     bool
+	CleanupOnGet    bool
+	SlidingTTL      bool
+	MonotonicClock  bool
+	EntryPooling    bool
+	Tracing         bool
+	OnEvict         bool
+	OnExpire        bool
+	ExpiryJitter    time.Duration
+	MaxBytes        int64
+}
+
+// Config returns the cache's current configuration.
+func (c *TTLCache[K, V]) Config() Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cfg := Config{
+		Size:            c.size,
+		DefaultTTL:      c.defaultTTL,
+		SweepPeriod:     c.sweepPeriod,
+		NoSweeper:       c.noSweeper,
+		EvictionPolicy:  c.eviction,
+		MaxKeyLength:    c.maxKeyLen,
+		AdaptiveTTL:     c.pressureFn != nil,
+		SkipEqualWrites: c.equalFn != nil,
+		SelfHealing:     c.selfHealing,
+		CleanupOnGet:    c.cleanupOnGet,
+		SlidingTTL:      c.slidingTTL,
+		MonotonicClock:  c.monotonic,
+		EntryPooling:    c.pool != nil,
+		Tracing:         c.tracer != nil,
+		OnEvict:         c.onEvict != nil,
+		OnExpire:        c.onExpire != nil,
+		ExpiryJitter:    c.maxJitter,
+		MaxBytes:        c.maxBytes,
+	}
+	if c.topK != nil {
+		cfg.TopKTracking = c.topK.k
+	}
+	if c.noSweeper {
+		cfg.SweepPeriod = 0
+	}
+	return cfg
+}
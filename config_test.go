@@ -0,0 +1,44 @@
+package ttl_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Defaults are reported when no options are given
+// --Options are reported
+func TestCache_Config(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}]()
+	require.Nil(t, err)
+	assert.Equal(t, Config{
+		Size:        DefaultSize,
+		DefaultTTL:  DefaultTTL,
+		SweepPeriod: DefaultSweepPeriod,
+	}, cache.Config())
+
+	cache, err = NewTTLCache[key, interface{}](WithSize(20), WithDefaultTTL(time.Minute), WithSweepPeriod(time.Second),
+		WithEvictionPolicy(EvictLRU),
+		WithMaxKeyLength(64),
+		WithTopKTracking(5),
+		WithCleanupOnGet(),
+		WithMonotonicClock(),
+		WithOnExpire(func(key, value interface{}) {}),
+	)
+	require.Nil(t, err)
+	assert.Equal(t, Config{
+		Size:           20,
+		DefaultTTL:     time.Minute,
+		SweepPeriod:    time.Second,
+		EvictionPolicy: EvictLRU,
+		MaxKeyLength:   64,
+		TopKTracking:   5,
+		CleanupOnGet:   true,
+		MonotonicClock: true,
+		OnExpire:       true,
+	}, cache.Config())
+}
@@ -0,0 +1,91 @@
+package ttl_cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// dumpedEntry is one entry as Dump writes it. Exp is the absolute expiry in Unix nanoseconds, omitted for a
+// pinned entry. Missing marks a SetMissing tombstone.
+type dumpedEntry[K comparable, V any] struct {
+	Key     K     `json:"key"`
+	Value   V     `json:"value"`
+	Exp     int64 `json:"exp,omitempty"`
+	Missing bool  `json:"missing,omitempty"`
+}
+
+// Dump writes every live entry to w as a JSON array of {"key", "value", "exp"} objects in ascending expiry
+// order, where exp is the absolute expiry in Unix nanoseconds and is left out for pinned entries. Entries are
+// encoded under the read lock and nothing is written if any key or value can't be encoded. A SetMissing
+// tombstone is written with "missing": true, so Load restores it as a tombstone.
+//
+// Keys and values are encoded with encoding/json, so they round-trip through Load as well as their types do
+// through json.Unmarshal. A concrete V such as a struct with exported fields comes back as it went in. An
+// interface V comes back as whatever json.Unmarshal makes of it in an interface{}: numbers become float64 and
+// structs become map[string]interface{}, so such a cache should only hold values that are already in those
+// forms.
+func (c *TTLCache[K, V]) Dump(w io.Writer) error {
+	c.mu.RLock()
+	buf, err := c.dump()
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func (c *TTLCache[K, V]) dump() (*bytes.Buffer, error) {
+	now := c.getExp(0)
+	buf := new(bytes.Buffer)
+	buf.WriteByte('[')
+	written := 0
+	for _, entry := range c.ttlHK {
+		if entry.expired(now) {
+			continue
+		}
+		de := dumpedEntry[K, V]{Key: entry.key, Value: entry.value, Missing: entry.missing}
+		if !entry.pinned() {
+			de.Exp = entry.exp
+		}
+		encoded, err := json.Marshal(de)
+		if err != nil {
+			return nil, newUnencodableEntryErr(entry.key, err)
+		}
+		if written > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(encoded)
+		written++
+	}
+	buf.WriteByte(']')
+	return buf, nil
+}
+
+// Load reads entries written by Dump from r and stores them with their saved expiries, so each keeps the TTL it
+// had left; entries that have expired since are skipped. Loaded entries overwrite existing keys and are
+// otherwise merged with what the cache holds, evicting as Set would once it is full. r is decoded in full
+// before the write lock is taken, and nothing is stored if it isn't valid JSON; otherwise Load stops at the
+// first error Set would return.
+func (c *TTLCache[K, V]) Load(r io.Reader) error {
+	var entries []dumpedEntry[K, V]
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, de := range entries {
+		exp := de.Exp
+		if exp == 0 {
+			exp = neverExpires
+		}
+		if err := c.restore(de.Key, de.Value, exp, de.Missing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package ttl_cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Live entries are written in expiry order with absolute exps
+// --SetMissing tombstones are marked missing
+// --Pinned entries have no exp
+// --Expired entries are skipped
+//
+// -Error
+// --Unencodable value returns an error and writes nothing
+func TestCache_Dump(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("later"), map[string]interface{}{"id": 1.0}, 60*time.Second))
+	require.Nil(t, cache.Set(key("sooner"), "value", 10*time.Second))
+	require.Nil(t, cache.Set(key("expired"), "value"))
+	cache.moveHKEntry(cache.cache[key("expired")], getExp(-time.Second))
+	require.Nil(t, cache.Set(key("pinned"), true))
+	require.Nil(t, cache.Pin(key("pinned")))
+	require.Nil(t, cache.SetMissing(key("missing"), 90*time.Second))
+
+	var buf bytes.Buffer
+	require.Nil(t, cache.Dump(&buf))
+	assert.JSONEq(t, fmt.Sprintf(`[
+		{"key": "sooner", "value": "value", "exp": %d},
+		{"key": "later", "value": {"id": 1}, "exp": %d},
+		{"key": "missing", "value": null, "exp": %d, "missing": true},
+		{"key": "pinned", "value": true}
+	]`, cache.cache[key("sooner")].exp, cache.cache[key("later")].exp, cache.cache[key("missing")].exp), buf.String())
+
+	require.Nil(t, cache.Set(key("channel"), make(chan int)))
+	buf.Reset()
+	err = cache.Dump(&buf)
+	assert.Contains(t, err.Error(), "entry for key channel")
+	var unsupported *json.UnsupportedTypeError
+	assert.True(t, errors.As(err, &unsupported))
+	assert.Zero(t, buf.Len())
+}
+
+// TestCases
+// -Success
+// --Dump, Clear and Load restores the live entries with their expiries
+// --A SetMissing tombstone round-trips as a tombstone
+// --Loading merges, overwriting existing keys
+// --Entries expired since the dump are skipped
+// --Loading past the size evicts
+//
+// -Error
+// --Invalid JSON stores nothing
+func TestCache_Load(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(3), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("a"), "a", 10*time.Second))
+	require.Nil(t, cache.Set(key("b"), "b", 60*time.Second))
+	require.Nil(t, cache.Set(key("pinned"), true))
+	require.Nil(t, cache.Pin(key("pinned")))
+	before := cache.EntriesSnapshot()
+
+	var buf bytes.Buffer
+	require.Nil(t, cache.Dump(&buf))
+	dumped := buf.String()
+	cache.Clear()
+	require.Nil(t, cache.Load(&buf))
+	assert.Equal(t, before, cache.EntriesSnapshot())
+	assert.Nil(t, cache.checkInvariants())
+
+	//A tombstone comes back as a cached miss, not as a cached zero value
+	cache.Clear()
+	require.Nil(t, cache.SetMissing(key("missing")))
+	buf.Reset()
+	require.Nil(t, cache.Dump(&buf))
+	cache.Clear()
+	require.Nil(t, cache.Load(&buf))
+	_, err = cache.Get(key("missing"))
+	assert.Equal(t, ErrCachedMiss, err)
+
+	cache.Clear()
+	require.Nil(t, cache.Set(key("a"), "stale"))
+	require.Nil(t, cache.Load(strings.NewReader(fmt.Sprintf(`[
+		{"key": "a", "value": "fresh", "exp": %d},
+		{"key": "gone", "value": "gone", "exp": %d}
+	]`, getExp(time.Minute), getExp(-time.Second)))))
+	assertKeyMapsToValue(t, "fresh", key("a"), cache)
+	assertKeyDoesNotExist(t, key("gone"), cache)
+
+	//Fill the cache so that the dump's new keys, b and pinned, each evict the soonest expiring entry
+	require.Nil(t, cache.Set(key("c"), "c", 5*time.Second))
+	require.Nil(t, cache.Set(key("d"), "d", 20*time.Second))
+	require.Nil(t, cache.Load(strings.NewReader(dumped)))
+	assertCacheHasNKeys(t, 3, cache)
+	assertKeyDoesNotExist(t, key("c"), cache)
+	assertKeyDoesNotExist(t, key("a"), cache)
+	assertKeyMapsToValue(t, "d", key("d"), cache)
+	assertKeyMapsToValue(t, "b", key("b"), cache)
+	assertKeyMapsToValue(t, true, key("pinned"), cache)
+
+	cache.Clear()
+	assert.NotNil(t, cache.Load(strings.NewReader(`[{"key": "a"`)))
+	assertCacheHasNKeys(t, 0, cache)
+}
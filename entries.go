@@ -0,0 +1,217 @@
+package ttl_cache
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Entry is a copy of a cached entry. A pinned entry has Pinned set and a zero ExpiresAt.
+type Entry[K comparable, V any] struct {
+	Key       K
+	Value     V
+	ExpiresAt time.Time
+	Pinned    bool
+}
+
+// EntriesSnapshot returns a copy of all live entries ordered by ascending expiry.
+func (c *TTLCache[K, V]) EntriesSnapshot() []Entry[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.entriesSnapshot()
+}
+
+func (c *TTLCache[K, V]) entriesSnapshot() []Entry[K, V] {
+	now := c.getExp(0)
+	entries := make([]Entry[K, V], 0, len(c.ttlHK))
+	for _, entry := range c.ttlHK {
+		if entry.expired(now) {
+			continue
+		}
+		entries = append(entries, entry.snapshot())
+	}
+	return entries
+}
+
+// Len returns the number of live entries. Entries that have expired but haven't been swept yet are not counted.
+func (c *TTLCache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	//ttlHK is sorted by exp, so expired entries are all at the front
+	now := c.getExp(0)
+	expired := sort.Search(len(c.ttlHK), func(i int) bool {
+		return !c.ttlHK[i].expired(now)
+	})
+	return len(c.ttlHK) - expired
+}
+
+// Keys returns the keys of all live entries. The order is stable: ascending expiry, as in EntriesSnapshot,
+// with pinned keys last.
+func (c *TTLCache[K, V]) Keys() []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.getExp(0)
+	keys := make([]K, 0, len(c.ttlHK))
+	for _, entry := range c.ttlHK {
+		if entry.expired(now) {
+			continue
+		}
+		keys = append(keys, entry.key)
+	}
+	return keys
+}
+
+// Range calls fn for each live entry in ascending expiry order, with pinned entries last, until fn returns
+// false. fn runs under the read lock, so it must not call back into the cache: a write from fn deadlocks.
+func (c *TTLCache[K, V]) Range(fn func(key K, value V) bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.getExp(0)
+	for _, entry := range c.ttlHK {
+		if entry.expired(now) {
+			continue
+		}
+		if !fn(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+// SortedEntries returns a copy of all live entries ordered by less.
+func (c *TTLCache[K, V]) SortedEntries(less func(a, b Entry[K, V]) bool) []Entry[K, V] {
+	entries := c.EntriesSnapshot()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return less(entries[i], entries[j])
+	})
+	return entries
+}
+
+func (e *cacheEntry[K, V]) snapshot() Entry[K, V] {
+	return Entry[K, V]{
+		Key:       e.key,
+		Value:     e.value,
+		ExpiresAt: e.expiresAt(),
+		Pinned:    e.pinned(),
+	}
+}
+
+// CacheDiff lists keys, in sorted order, that were added, removed or whose value changed since a snapshot.
+type CacheDiff[K comparable] struct {
+	Added   []K
+	Removed []K
+	Changed []K
+}
+
+// Diff compares the live entries against a previous EntriesSnapshot, comparing values with reflect.DeepEqual.
+func (c *TTLCache[K, V]) Diff(previous []Entry[K, V]) CacheDiff[K] {
+	return c.DiffFunc(previous, func(a, b V) bool {
+		return reflect.DeepEqual(a, b)
+	})
+}
+
+// DiffFunc is like Diff but compares values with equal.
+func (c *TTLCache[K, V]) DiffFunc(previous []Entry[K, V], equal func(a, b V) bool) CacheDiff[K] {
+	var diff CacheDiff[K]
+	snapshot := c.EntriesSnapshot()
+	current := make(map[K]Entry[K, V], len(snapshot))
+	for _, entry := range snapshot {
+		current[entry.Key] = entry
+	}
+
+	seen := make(map[K]struct{}, len(previous))
+	for _, prev := range previous {
+		seen[prev.Key] = struct{}{}
+		cur, exists := current[prev.Key]
+		switch {
+		case !exists:
+			diff.Removed = append(diff.Removed, prev.Key)
+		case !equal(prev.Value, cur.Value):
+			diff.Changed = append(diff.Changed, prev.Key)
+		}
+	}
+	for k := range current {
+		if _, existed := seen[k]; !existed {
+			diff.Added = append(diff.Added, k)
+		}
+	}
+
+	sortKeys(diff.Added)
+	sortKeys(diff.Removed)
+	sortKeys(diff.Changed)
+	return diff
+}
+
+func sortKeys[K comparable](keys []K) {
+	sort.Slice(keys, func(i, j int) bool {
+		return keyLess(keys[i], keys[j])
+	})
+}
+
+// AgeHistogram counts live entries by time since they were first stored. buckets are ascending upper bounds:
+// counts[i] holds entries younger than buckets[i] but not younger than buckets[i-1], and the extra final count
+// holds entries at least as old as the last bound.
+func (c *TTLCache[K, V]) AgeHistogram(buckets []time.Duration) []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.getExp(0)
+	counts := make([]int, len(buckets)+1)
+	for _, entry := range c.ttlHK {
+		if entry.expired(now) {
+			continue
+		}
+		age := time.Duration(now - entry.createdAt)
+		i := sort.Search(len(buckets), func(i int) bool {
+			return age < buckets[i]
+		})
+		counts[i]++
+	}
+	return counts
+}
+
+// NeighborsByExpiry returns up to window live entries on each side of key in expiry order, excluding key
+// itself. A window that isn't positive returns no neighbors.
+func (c *TTLCache[K, V]) NeighborsByExpiry(key K, window int) ([]Entry[K, V], error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.getExp(0)
+	entry, exists := c.cache[key]
+	if !exists || entry.expired(now) {
+		return nil, newKeyNotFoundErr(key)
+	}
+	i := c.indexOfHKEntry(entry)
+	if i < 0 {
+		return nil, newKeyNotFoundErr(key)
+	}
+
+	firstLive := sort.Search(len(c.ttlHK), func(i int) bool {
+		return !c.ttlHK[i].expired(now)
+	})
+	//Clamp window so that i±window can neither run backwards nor overflow
+	if window < 0 {
+		window = 0
+	}
+	if window > len(c.ttlHK) {
+		window = len(c.ttlHK)
+	}
+	start, end := i-window, i+window+1
+	if start < firstLive {
+		start = firstLive
+	}
+	if end > len(c.ttlHK) {
+		end = len(c.ttlHK)
+	}
+
+	neighbors := make([]Entry[K, V], 0, end-start)
+	for j := start; j < end; j++ {
+		if j != i {
+			neighbors = append(neighbors, c.ttlHK[j].snapshot())
+		}
+	}
+	return neighbors, nil
+}
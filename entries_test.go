@@ -0,0 +1,211 @@
+package ttl_cache
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// TestCases
+// -Success
+// --Snapshot is ordered by expiry and skips expired entries
+// --Sort by expiry, latest first
+// --Sort by a value-derived field, largest first
+// --Len counts only live entries
+// --Keys lists only live keys in expiry order
+// --Range visits only live entries in expiry order
+// --Range stops once fn returns false
+func TestCache_Entries(t *testing.T) {
+	es := new(entriesSuite)
+	suite.Run(t, es)
+}
+
+type entriesSuite struct {
+	cacheSuite
+}
+
+type sizedValue struct {
+	size int
+}
+
+func (es *entriesSuite) SetupTest() {
+	es.cacheSuite.SetupSuite()
+	require.Nil(es.T(), es.cache.Set(key("medium"), sizedValue{size: 2}, 20*time.Second))
+	require.Nil(es.T(), es.cache.Set(key("small"), sizedValue{size: 1}, 30*time.Second))
+	require.Nil(es.T(), es.cache.Set(key("large"), sizedValue{size: 3}, 10*time.Second))
+
+	expired := newCacheEntry[key, interface{}](key("expired"), sizedValue{size: 4}, time.Now().Add(-5*time.Second).UnixNano())
+	es.cache.cache[expired.key] = expired
+	es.cache.insertNewHKEntry(expired)
+}
+
+func (es *entriesSuite) TestEntriesSnapshot() {
+	entries := es.cache.EntriesSnapshot()
+	assert.Equal(es.T(), []key{"large", "medium", "small"}, entryKeys(entries))
+	assert.WithinDuration(es.T(), time.Now().Add(10*time.Second), entries[0].ExpiresAt, time.Second)
+	assert.Equal(es.T(), sizedValue{size: 3}, entries[0].Value)
+}
+
+func (es *entriesSuite) TestSortedEntries_ByExpiryDescending() {
+	entries := es.cache.SortedEntries(func(a, b Entry[key, interface{}]) bool {
+		return a.ExpiresAt.After(b.ExpiresAt)
+	})
+	assert.Equal(es.T(), []key{"small", "medium", "large"}, entryKeys(entries))
+}
+
+func (es *entriesSuite) TestSortedEntries_ByValue() {
+	entries := es.cache.SortedEntries(func(a, b Entry[key, interface{}]) bool {
+		return a.Value.(sizedValue).size > b.Value.(sizedValue).size
+	})
+	assert.Equal(es.T(), []key{"large", "medium", "small"}, entryKeys(entries))
+}
+
+func (es *entriesSuite) TestLen() {
+	assert.Equal(es.T(), 3, es.cache.Len())
+
+	require.Nil(es.T(), es.cache.Pin(key("small")))
+	es.cache.moveHKEntry(es.cache.cache[key("large")], getExp(-time.Second))
+	assert.Equal(es.T(), 2, es.cache.Len())
+}
+
+func (es *entriesSuite) TestKeys() {
+	assert.Equal(es.T(), []key{"large", "medium", "small"}, es.cache.Keys())
+
+	require.Nil(es.T(), es.cache.Pin(key("large")))
+	es.cache.moveHKEntry(es.cache.cache[key("medium")], getExp(-time.Second))
+	assert.Equal(es.T(), []key{"small", "large"}, es.cache.Keys())
+}
+
+func (es *entriesSuite) TestRange() {
+	var visited []key
+	es.cache.Range(func(k key, value interface{}) bool {
+		visited = append(visited, k)
+		assertKeyMapsToValue(es.T(), value, k, es.cache)
+		return true
+	})
+	assert.Equal(es.T(), []key{"large", "medium", "small"}, visited)
+
+	calls := 0
+	es.cache.Range(func(key, interface{}) bool {
+		calls++
+		return calls < 2
+	})
+	assert.Equal(es.T(), 2, calls)
+}
+
+func entryKeys(entries []Entry[key, interface{}]) []key {
+	keys := make([]key, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+// TestCases
+// -Success
+// --Adds, removes and changes are classified
+// --Unchanged cache has an empty diff
+// --Custom comparator decides what changed
+func TestCache_Diff(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("kept"), []int{1, 2}))
+	require.Nil(t, cache.Set(key("changed"), "before"))
+	require.Nil(t, cache.Set(key("removed"), "value"))
+
+	snapshot := cache.EntriesSnapshot()
+	assert.Equal(t, CacheDiff[key]{}, cache.Diff(snapshot))
+
+	require.Nil(t, cache.Set(key("kept"), []int{1, 2}))
+	require.Nil(t, cache.Set(key("changed"), "after"))
+	require.Nil(t, cache.Set(key("added"), "value"))
+	require.Nil(t, cache.Delete(key("removed")))
+
+	assert.Equal(t, CacheDiff[key]{
+		Added:   []key{"added"},
+		Removed: []key{"removed"},
+		Changed: []key{"changed"},
+	}, cache.Diff(snapshot))
+
+	alwaysEqual := func(a, b interface{}) bool { return true }
+	assert.Equal(t, CacheDiff[key]{
+		Added:   []key{"added"},
+		Removed: []key{"removed"},
+	}, cache.DiffFunc(snapshot, alwaysEqual))
+}
+
+// TestCases
+// -Success
+// --Entries land in the bucket for their age
+// --No buckets counts everything in the overflow
+func TestCache_AgeHistogram(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(5*time.Minute), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	ages := map[key]time.Duration{
+		"new":     0,
+		"young":   5 * time.Second,
+		"mature1": 30 * time.Second,
+		"mature2": 45 * time.Second,
+		"old":     2 * time.Minute,
+	}
+	for k, age := range ages {
+		require.Nil(t, cache.Set(k, "value"))
+		cache.cache[k].createdAt = getExp(-age)
+	}
+
+	buckets := []time.Duration{10 * time.Second, time.Minute}
+	assert.Equal(t, []int{2, 2, 1}, cache.AgeHistogram(buckets))
+	assert.Equal(t, []int{5}, cache.AgeHistogram(nil))
+}
+
+// TestCases
+// -Success
+// --Neighbors on both sides in expiry order
+// --Window clipped at the front, skipping expired entries
+// --Window clipped at the back
+// --A huge window returns every other live entry
+// --A window that isn't positive returns nothing
+//
+// -Error
+// --Missing key
+func TestCache_NeighborsByExpiry(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	for i, k := range []key{"a", "b", "c", "d", "e"} {
+		require.Nil(t, cache.Set(k, "value", time.Duration(i+1)*10*time.Second))
+	}
+	expired := newCacheEntry[key, interface{}](key("expired"), "value", time.Now().Add(-5*time.Second).UnixNano())
+	cache.cache[expired.key] = expired
+	cache.insertNewHKEntry(expired)
+
+	neighbors, err := cache.NeighborsByExpiry(key("c"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []key{"b", "d"}, entryKeys(neighbors))
+
+	neighbors, err = cache.NeighborsByExpiry(key("a"), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []key{"b", "c"}, entryKeys(neighbors))
+
+	neighbors, err = cache.NeighborsByExpiry(key("e"), 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []key{"b", "c", "d"}, entryKeys(neighbors))
+
+	neighbors, err = cache.NeighborsByExpiry(key("c"), math.MaxInt)
+	assert.Nil(t, err)
+	assert.Equal(t, []key{"a", "b", "d", "e"}, entryKeys(neighbors))
+
+	for _, window := range []int{0, -1, math.MinInt} {
+		neighbors, err = cache.NeighborsByExpiry(key("c"), window)
+		assert.Nil(t, err)
+		assert.Empty(t, neighbors)
+	}
+
+	neighbors, err = cache.NeighborsByExpiry(key("missing"), 1)
+	assert.Equal(t, newKeyNotFoundErr(key("missing")), err)
+	assert.Nil(t, neighbors)
+}
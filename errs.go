@@ -1,10 +1,17 @@
 package ttl_cache
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
 
+var (
+	ErrKeyTooLong  = errors.New("key too long")
+	ErrCacheClosed = errors.New("cache closed")
+	ErrCachedMiss  = errors.New("cached miss")
+)
+
 func newInvalidSweepPeriodErr(invalidDur time.Duration) error {
 	return fmt.Errorf("invalid sweep period %s; must be > 0s", invalidDur)
 }
@@ -17,10 +24,70 @@ func newInvalidSizeErr(invalidSize uint) error {
 	return fmt.Errorf("invalid cache size %d; must be > 0", invalidSize)
 }
 
-func newBadUpdateRequestErr(invalidKey key) error {
-	return fmt.Errorf("invalid key for update request %s", invalidKey)
+func newInvalidMaxBytesErr(invalidMaxBytes int64) error {
+	return fmt.Errorf("invalid max bytes %d; must be > 0", invalidMaxBytes)
+}
+
+func newNilSizerErr() error {
+	return fmt.Errorf("WithMaxBytes needs a sizer; got nil")
+}
+
+func newInvalidShardCountErr(invalidCount int) error {
+	return fmt.Errorf("invalid shard count %d; must be a power of two > 0", invalidCount)
+}
+
+func newBadUpdateRequestErr[K comparable](invalidKey K) error {
+	return fmt.Errorf("invalid key for update request %v", invalidKey)
+}
+
+func newKeyTooLongErr(keyLen, maxKeyLen int) error {
+	return fmt.Errorf("%w: %d bytes exceeds max of %d", ErrKeyTooLong, keyLen, maxKeyLen)
+}
+
+func newCacheFullErr[K comparable](rejectedKey K) error {
+	return fmt.Errorf("cache full; no entry could be evicted to store key %v", rejectedKey)
+}
+
+func newUnencodableEntryErr[K comparable](invalidKey K, err error) error {
+	return fmt.Errorf("entry for key %v cannot be encoded as JSON: %w", invalidKey, err)
+}
+
+func newEntryTooLargeErr[K comparable](rejectedKey K, cost, maxBytes int64) error {
+	return fmt.Errorf("value for key %v is %d bytes; must be <= the cache's %d byte limit", rejectedKey, cost, maxBytes)
+}
+
+func newLoaderPanicErr[K comparable](panickedKey K) error {
+	return fmt.Errorf("loader for key %v panicked", panickedKey)
+}
+
+func newKeyNotFoundErr[K comparable](notFoundKey K) error {
+	return fmt.Errorf("key %v not found", notFoundKey)
+}
+
+func newIndexLenMismatchErr(cacheLen, indexLen int) error {
+	return fmt.Errorf("cache holds %d entries but ttlHK holds %d", cacheLen, indexLen)
+}
+
+func newIndexEntryErr[K comparable](invalidKey K, pos int) error {
+	return fmt.Errorf("ttlHK entry %d for key %v does not match the cache entry", pos, invalidKey)
+}
+
+func newIndexOrderErr(pos int) error {
+	return fmt.Errorf("ttlHK out of order at position %d", pos)
+}
+
+func newBytesMismatchErr(entryBytes, recordedBytes int64) error {
+	return fmt.Errorf("entries cost %d bytes but the cache records %d", entryBytes, recordedBytes)
+}
+
+func newInvalidParentErr[K comparable](invalidKey K) error {
+	return fmt.Errorf("key %v cannot be its own parent", invalidKey)
+}
+
+func newNotPinnedErr[K comparable](invalidKey K) error {
+	return fmt.Errorf("key %v is not pinned", invalidKey)
 }
 
-func newKeyNotFoundErr(notFoundKey key) error {
-	return fmt.Errorf("key %s not found", notFoundKey)
+func newNoKeysFoundErr[K comparable](notFoundKeys []K) error {
+	return fmt.Errorf("none of keys %v found", notFoundKeys)
 }
@@ -0,0 +1,125 @@
+package ttl_cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// EvictionPolicy picks which entry Set evicts to make room for a new key in a full cache. Pinned entries are
+// never evicted under any policy.
+type EvictionPolicy int
+
+const (
+	// EvictSoonestExpiry evicts the entry closest to expiring, the front of ttlHK. It costs nothing extra.
+	EvictSoonestExpiry EvictionPolicy = iota
+	// EvictLRU evicts the entry least recently stored or read with Get. The entry's last access time is
+	// recorded on every Set and Get, and choosing a victim scans every entry, so each eviction is O(n) rather
+	// than O(1). No second ordering is kept, which keeps Get on the read lock and memory at 8 bytes per entry.
+	EvictLRU
+)
+
+func (p EvictionPolicy) String() string {
+	switch p {
+	case EvictSoonestExpiry:
+		return "soonest-expiry"
+	case EvictLRU:
+		return "lru"
+	}
+	return "unknown"
+}
+
+// makeRoom evicts an entry chosen by the eviction policy, other than the keep entries and their SetChild
+// parents, to free a slot for key.
+func (c *TTLCache[K, V]) makeRoom(key K, keep ...*cacheEntry[K, V]) error {
+	victim := c.victim(keep...)
+	if victim == nil {
+		return newCacheFullErr(key)
+	}
+	c.removeEntry(victim, ChangeEvict)
+	if uint(len(c.cache)) >= c.size {
+		return newCacheFullErr(key)
+	}
+	return nil
+}
+
+// Resize changes how many entries the cache holds. Shrinking below the current count first reaps expired
+// entries as a sweep would, then evicts the entries the eviction policy picks, soonest expiry first by default,
+// until the cache fits. Pinned entries are never evicted, so a cache pinned beyond newSize stays over it until
+// they are removed, and Sets of new keys fail with a cache full error meanwhile.
+func (c *TTLCache[K, V]) Resize(newSize uint) error {
+	if newSize <= 0 {
+		return newInvalidSizeErr(newSize)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.size = newSize
+	if uint(len(c.cache)) <= c.size {
+		return nil
+	}
+	c.evict(c.getExp(0))
+	for uint(len(c.cache)) > c.size {
+		victim := c.victim()
+		if victim == nil {
+			break
+		}
+		c.removeEntry(victim, ChangeEvict)
+	}
+	return nil
+}
+
+// victim returns the entry to evict other than the except entries and the parents they are attached to with
+// SetChild, whose removal would take them along, or nil if every other entry is pinned. Pinned entries sort to
+// the back of ttlHK, so reaching a pinned entry means there is nothing left to evict.
+func (c *TTLCache[K, V]) victim(except ...*cacheEntry[K, V]) *cacheEntry[K, V] {
+	var oldest *cacheEntry[K, V]
+	for _, entry := range c.ttlHK {
+		if entry.pinned() {
+			break
+		}
+		if c.protectsAny(except, entry) {
+			continue
+		}
+		if c.eviction != EvictLRU {
+			return entry
+		}
+		if oldest == nil || entry.accessedAt() < oldest.accessedAt() {
+			oldest = entry
+		}
+	}
+	return oldest
+}
+
+func (c *TTLCache[K, V]) protectsAny(except []*cacheEntry[K, V], entry *cacheEntry[K, V]) bool {
+	for _, e := range except {
+		if c.protects(e, entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// protects reports whether entry is except or one of its SetChild ancestors. The walk is bounded by the cache's
+// size in case parent links form a cycle.
+func (c *TTLCache[K, V]) protects(except, entry *cacheEntry[K, V]) bool {
+	for hops := 0; except != nil && hops <= len(c.cache); hops++ {
+		if entry == except {
+			return true
+		}
+		if !except.hasParent {
+			return false
+		}
+		except = c.cache[except.parent]
+	}
+	return false
+}
+
+// touch records an access. Get holds only the read lock, so the time is stored atomically.
+func (e *cacheEntry[K, V]) touch() {
+	atomic.StoreInt64(&e.lastAccess, time.Now().UnixNano())
+}
+
+func (e *cacheEntry[K, V]) accessedAt() int64 {
+	return atomic.LoadInt64(&e.lastAccess)
+}
@@ -0,0 +1,106 @@
+package ttl_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Soonest expiry evicts the front of ttlHK even if it was just read
+// --LRU evicts the least recently accessed entry
+// --LRU never evicts a pinned entry
+// --Peek does not count as an access for LRU
+func TestCache_EvictionPolicy(t *testing.T) {
+	fill := func(policy EvictionPolicy) *TTLCache[key, interface{}] {
+		cache, err := NewTTLCache[key, interface{}](WithSize(3), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithEvictionPolicy(policy))
+		require.Nil(t, err)
+		require.Nil(t, cache.Set(key("a"), "value", time.Minute))
+		require.Nil(t, cache.Set(key("b"), "value", 2*time.Minute))
+		require.Nil(t, cache.Set(key("c"), "value", 3*time.Minute))
+		return cache
+	}
+
+	t.Run("soonest expiry", func(t *testing.T) {
+		cache := fill(EvictSoonestExpiry)
+		_, err := cache.Get(key("a"))
+		require.Nil(t, err)
+
+		require.Nil(t, cache.Set(key("d"), "value"))
+		assertKeyDoesNotExist(t, key("a"), cache)
+		assertCacheHasNKeys(t, 3, cache)
+	})
+
+	t.Run("lru", func(t *testing.T) {
+		cache := fill(EvictLRU)
+		_, err := cache.Get(key("a"))
+		require.Nil(t, err)
+		require.Nil(t, cache.Set(key("c"), "overwritten", 3*time.Minute))
+
+		require.Nil(t, cache.Set(key("d"), "value"))
+		assertKeyDoesNotExist(t, key("b"), cache)
+		assertKeyMapsToValue(t, "value", key("a"), cache)
+		assertCacheHasNKeys(t, 3, cache)
+	})
+
+	t.Run("lru skips pinned", func(t *testing.T) {
+		cache := fill(EvictLRU)
+		require.Nil(t, cache.Pin(key("a")))
+
+		require.Nil(t, cache.Set(key("d"), "value"))
+		assert.True(t, cache.cache[key("a")].pinned())
+		assertKeyDoesNotExist(t, key("b"), cache)
+	})
+	t.Run("lru ignores peek", func(t *testing.T) {
+		cache := fill(EvictLRU)
+		value, err := cache.Peek(key("a"))
+		require.Nil(t, err)
+		assert.Equal(t, "value", value)
+
+		require.Nil(t, cache.Set(key("d"), "value"))
+		assertKeyDoesNotExist(t, key("a"), cache)
+	})
+}
+
+// TestCases
+// -Success
+// --Growing lets more keys in without evicting
+// --Shrinking reaps expired entries, then evicts soonest expiry first
+// --Pinned entries are kept even if they leave the cache over size
+//
+// -Error
+// --Zero size is rejected and the size is unchanged
+func TestCache_Resize(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(3), WithDefaultTTL(30*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("a"), "value", time.Minute))
+	require.Nil(t, cache.Set(key("b"), "value", 2*time.Minute))
+	require.Nil(t, cache.Set(key("c"), "value", 3*time.Minute))
+
+	require.Nil(t, cache.Resize(5))
+	require.Nil(t, cache.Set(key("d"), "value", 4*time.Minute))
+	require.Nil(t, cache.Set(key("e"), "value", 5*time.Minute))
+	assertCacheHasNKeys(t, 5, cache)
+	assert.Equal(t, uint(5), cache.Config().Size)
+
+	cache.moveHKEntry(cache.cache[key("c")], getExp(-time.Second))
+	events, unsubscribe := cache.Subscribe(key("c"), 1)
+	defer unsubscribe()
+	require.Nil(t, cache.Resize(2))
+	assert.Equal(t, []key{"d", "e"}, cache.Keys())
+	assert.Equal(t, ChangeEvent[interface{}]{Type: ChangeExpire, Value: "value"}, <-events)
+	assert.Equal(t, Stats{Evictions: 2, Expirations: 1, Sets: 5}, cache.Stats())
+	assert.Nil(t, cache.checkInvariants())
+
+	require.Nil(t, cache.Pin(key("d")))
+	require.Nil(t, cache.Pin(key("e")))
+	require.Nil(t, cache.Resize(1))
+	assertCacheHasNKeys(t, 2, cache)
+	assert.Equal(t, newCacheFullErr(key("f")), cache.Set(key("f"), "value"))
+
+	assert.Equal(t, newInvalidSizeErr(0), cache.Resize(0))
+	assert.Equal(t, uint(1), cache.Config().Size)
+}
@@ -0,0 +1,89 @@
+package ttl_cache
+
+import "time"
+
+// SetChild stores value under key as a child of parentKey. The child's expiry is capped at the parent's, and
+// expiring or removing the parent removes the child with it. The cap holds for as long as the child stays
+// attached: a later Set, Touch, ExtendOnly, Pin or sliding refresh can't move the child's expiry past the
+// parent's, and moving the parent's expiry earlier moves the child's with it. Making room for the child never
+// evicts the parent.
+func (c *TTLCache[K, V]) SetChild(parentKey, key K, value V, optTTL ...time.Duration) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if parentKey == key {
+		return newInvalidParentErr(key)
+	}
+	parent, exists := c.cache[parentKey]
+	if !exists || parent.expired(c.getExp(0)) {
+		return newKeyNotFoundErr(parentKey)
+	}
+
+	exp := c.writeExp(optTTL)
+	if parent.exp < exp {
+		exp = parent.exp
+	}
+	entry, err := c.write(key, value, exp, false, parent)
+	if err != nil {
+		return err
+	}
+
+	c.linkToParent(entry, parentKey)
+	return nil
+}
+
+// capToParent returns exp, or entry's parent's exp if entry is attached to a parent that expires sooner.
+func (c *TTLCache[K, V]) capToParent(entry *cacheEntry[K, V], exp int64) int64 {
+	if !entry.hasParent {
+		return exp
+	}
+	if parent, exists := c.cache[entry.parent]; exists && parent.exp < exp {
+		return parent.exp
+	}
+	return exp
+}
+
+// capChildren moves entry's children that expire after it back to its exp.
+func (c *TTLCache[K, V]) capChildren(entry *cacheEntry[K, V]) {
+	for childKey := range c.children[entry.key] {
+		if child, exists := c.cache[childKey]; exists && child.parent == entry.key && child.exp > entry.exp {
+			c.moveHKEntry(child, entry.exp)
+		}
+	}
+}
+
+func (c *TTLCache[K, V]) linkToParent(entry *cacheEntry[K, V], parentKey K) {
+	c.unlinkFromParent(entry)
+	entry.parent = parentKey
+	entry.hasParent = true
+	if c.children[parentKey] == nil {
+		c.children[parentKey] = make(map[K]struct{})
+	}
+	c.children[parentKey][entry.key] = struct{}{}
+}
+
+// detach drops entry from the parent index, removing any children still in the cache.
+func (c *TTLCache[K, V]) detach(entry *cacheEntry[K, V], changeType ChangeType) {
+	c.unlinkFromParent(entry)
+
+	children := c.children[entry.key]
+	delete(c.children, entry.key)
+	for childKey := range children {
+		if child, exists := c.cache[childKey]; exists && child.parent == entry.key {
+			c.removeEntry(child, changeType)
+		}
+	}
+}
+
+func (c *TTLCache[K, V]) unlinkFromParent(entry *cacheEntry[K, V]) {
+	if !entry.hasParent {
+		return
+	}
+	if siblings := c.children[entry.parent]; siblings != nil {
+		delete(siblings, entry.key)
+		if len(siblings) == 0 {
+			delete(c.children, entry.parent)
+		}
+	}
+	entry.hasParent = false
+}
@@ -0,0 +1,153 @@
+package ttl_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// TestCases
+// -Success
+// --Child expiry capped by parent
+// --Child with a shorter TTL keeps its own expiry
+// --Parent expiring removes its children
+// --Parent removal removes its children
+// --Set, Touch, TouchPrefix, ExtendOnly and sliding refreshes can't move a child past its parent
+// --Moving the parent's expiry earlier pulls its child in
+//
+// -Error
+// --Missing parent
+// --Key is its own parent
+func TestCache_SetChild(t *testing.T) {
+	hc := new(hierarchySuite)
+	suite.Run(t, hc)
+}
+
+type hierarchySuite struct {
+	parentKey key
+	cacheSuite
+}
+
+func (hc *hierarchySuite) SetupTest() {
+	hc.cacheSuite.SetupSuite()
+	hc.parentKey = key("session")
+	require.Nil(hc.T(), hc.cache.Set(hc.parentKey, "parent", 10*time.Second))
+}
+
+func (hc *hierarchySuite) TestSetChild_CappedByParent() {
+	err := hc.cache.SetChild(hc.parentKey, key("child"), "child", 60*time.Second)
+	require.Nil(hc.T(), err)
+
+	assert.Equal(hc.T(), hc.cache.cache[hc.parentKey].exp, hc.cache.cache[key("child")].exp)
+	assertKeyMapsToValue(hc.T(), "child", key("child"), hc.cache)
+}
+
+func (hc *hierarchySuite) TestSetChild_ShorterTTLKept() {
+	err := hc.cache.SetChild(hc.parentKey, key("child"), "child", 5*time.Second)
+	require.Nil(hc.T(), err)
+
+	assertExpNear(hc.T(), getExp(5*time.Second), hc.cache.cache[key("child")].exp)
+}
+
+func (hc *hierarchySuite) TestSetChild_ParentExpiryCascades() {
+	require.Nil(hc.T(), hc.cache.SetChild(hc.parentKey, key("child1"), "child1"))
+	require.Nil(hc.T(), hc.cache.SetChild(hc.parentKey, key("child2"), "child2", 5*time.Second))
+	require.Nil(hc.T(), hc.cache.Set(key("unrelated"), "unrelated", 5*time.Second))
+
+	hc.cache.evict(getExp(11 * time.Second))
+
+	assertKeyDoesNotExist(hc.T(), hc.parentKey, hc.cache)
+	assertKeyDoesNotExist(hc.T(), key("child1"), hc.cache)
+	assertKeyDoesNotExist(hc.T(), key("child2"), hc.cache)
+	assertCacheHasNKeys(hc.T(), 0, hc.cache)
+	assert.Empty(hc.T(), hc.cache.children)
+}
+
+func (hc *hierarchySuite) TestSetChild_ParentRemovalCascades() {
+	require.Nil(hc.T(), hc.cache.SetChild(hc.parentKey, key("child"), "child"))
+	require.Nil(hc.T(), hc.cache.Set(key("unrelated"), "unrelated"))
+
+	require.Nil(hc.T(), hc.cache.Delete(hc.parentKey))
+
+	assertKeyDoesNotExist(hc.T(), hc.parentKey, hc.cache)
+	assertKeyDoesNotExist(hc.T(), key("child"), hc.cache)
+	assertKeyMapsToValue(hc.T(), "unrelated", key("unrelated"), hc.cache)
+	assertCacheHasNKeys(hc.T(), 1, hc.cache)
+	assert.Empty(hc.T(), hc.cache.children)
+}
+
+func (hc *hierarchySuite) TestSetChild_CapHoldsOnRefresh() {
+	require.Nil(hc.T(), hc.cache.SetChild(hc.parentKey, key("child"), "child", 5*time.Second))
+	parentExp := hc.cache.cache[hc.parentKey].exp
+
+	require.Nil(hc.T(), hc.cache.Touch(key("child"), time.Hour))
+	assert.Equal(hc.T(), parentExp, hc.cache.cache[key("child")].exp)
+	require.Nil(hc.T(), hc.cache.Set(key("child"), "overwritten", time.Hour))
+	assert.Equal(hc.T(), parentExp, hc.cache.cache[key("child")].exp)
+	assert.Equal(hc.T(), 1, hc.cache.TouchPrefix("chi", time.Hour))
+	assert.Equal(hc.T(), parentExp, hc.cache.cache[key("child")].exp)
+	extended, err := hc.cache.ExtendOnly(key("child"), time.Hour)
+	assert.Nil(hc.T(), err)
+	assert.False(hc.T(), extended)
+	assert.Nil(hc.T(), hc.cache.checkInvariants())
+
+	//The parent expires first, so the child is gone with it even before a sweep
+	hc.cache.moveHKEntry(hc.cache.cache[hc.parentKey], getExp(-time.Second))
+	assertKeyDoesNotExist(hc.T(), hc.parentKey, hc.cache)
+	assertKeyDoesNotExist(hc.T(), key("child"), hc.cache)
+	assert.Nil(hc.T(), hc.cache.checkInvariants())
+}
+
+func (hc *hierarchySuite) TestSetChild_CapHoldsOnSlidingRefresh() {
+	cache, err := NewTTLCache[key, interface{}](WithDefaultTTL(time.Hour), WithSlidingTTL())
+	require.Nil(hc.T(), err)
+	require.Nil(hc.T(), cache.Set(hc.parentKey, "parent", 10*time.Second))
+	require.Nil(hc.T(), cache.SetChild(hc.parentKey, key("child"), "child", 5*time.Second))
+
+	_, err = cache.Get(key("child"))
+	require.Nil(hc.T(), err)
+	assert.Equal(hc.T(), cache.cache[hc.parentKey].exp, cache.cache[key("child")].exp)
+}
+
+func (hc *hierarchySuite) TestSetChild_MissingParent() {
+	err := hc.cache.SetChild(key("missing"), key("child"), "child")
+	assert.Equal(hc.T(), newKeyNotFoundErr(key("missing")), err)
+	assertKeyDoesNotExist(hc.T(), key("child"), hc.cache)
+}
+
+func (hc *hierarchySuite) TestSetChild_OwnParent() {
+	err := hc.cache.SetChild(hc.parentKey, hc.parentKey, "child")
+	assert.Equal(hc.T(), newInvalidParentErr(hc.parentKey), err)
+}
+
+// TestCases
+// -Success
+// --A full cache evicts another entry rather than the child's parent
+//
+// -Error
+// --A full cache holding only the parent rejects the child and leaves no dangling link
+func TestCache_SetChild_FullCache(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(2), WithDefaultTTL(30*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("parent"), "parent", 10*time.Second))
+	require.Nil(t, cache.Set(key("other"), "other", time.Minute))
+
+	//parent expires soonest, but evicting it would orphan the child
+	require.Nil(t, cache.SetChild(key("parent"), key("child"), "child"))
+	assertKeyDoesNotExist(t, key("other"), cache)
+	assertKeyMapsToValue(t, "parent", key("parent"), cache)
+	assertKeyMapsToValue(t, "child", key("child"), cache)
+	require.Nil(t, cache.Delete(key("parent")))
+	assertCacheHasNKeys(t, 0, cache)
+
+	require.Nil(t, cache.Resize(1))
+	require.Nil(t, cache.Set(key("parent"), "parent"))
+	assert.Equal(t, newCacheFullErr(key("child")), cache.SetChild(key("parent"), key("child"), "child"))
+	assertKeyMapsToValue(t, "parent", key("parent"), cache)
+	assertKeyDoesNotExist(t, key("child"), cache)
+	assert.Empty(t, cache.children)
+	assert.Nil(t, cache.checkInvariants())
+}
@@ -0,0 +1,32 @@
+package ttl_cache
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// keyString returns k as a string if the key type is a string type.
+func keyString[K comparable](k K) (string, bool) {
+	v := reflect.ValueOf(k)
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
+
+// keyLess orders keys for results documented as sorted: numerically for integer and float keys, by value for
+// string keys, and by their %v formatting otherwise.
+func keyLess[K comparable](a, b K) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.String:
+		return va.String() < vb.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return va.Int() < vb.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return va.Uint() < vb.Uint()
+	case reflect.Float32, reflect.Float64:
+		return va.Float() < vb.Float()
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
@@ -0,0 +1,29 @@
+package ttl_cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCases
+// -Success
+// --String-kind keys convert to their string
+// --Other keys have no string form
+// --Numeric keys order numerically, others by value or formatting
+func TestKeys_Helpers(t *testing.T) {
+	s, ok := keyString(key("named"))
+	assert.True(t, ok)
+	assert.Equal(t, "named", s)
+
+	_, ok = keyString(42)
+	assert.False(t, ok)
+
+	type point struct{ x, y int }
+	assert.True(t, keyLess(9, 10))
+	assert.True(t, keyLess(uint8(2), uint8(3)))
+	assert.True(t, keyLess(-1.5, 0.5))
+	assert.True(t, keyLess(key("a"), key("b")))
+	assert.True(t, keyLess(point{1, 2}, point{1, 3}))
+	assert.False(t, keyLess(10, 9))
+}
@@ -0,0 +1,179 @@
+package ttl_cache
+
+import (
+	"context"
+	"time"
+)
+
+// GetOrSetEach returns the live value for every requested key, running the key's loader for each miss and
+// storing what it returns. Loaders run one at a time without holding the cache's lock, at most once per key,
+// and share GetOrSet's single flight: a miss that another GetOrSet or GetOrSetEach is already loading waits for
+// that load instead of running its own loader. A loader error is reported for its key and nothing is stored for
+// it. A key with a SetMissing tombstone is reported as ErrCachedMiss without running its loader.
+func (c *TTLCache[K, V]) GetOrSetEach(requests map[K]func() (V, error), optTTL ...time.Duration) (map[K]V, map[K]error) {
+	values := make(map[K]V, len(requests))
+	errs := make(map[K]error)
+
+	c.mu.RLock()
+	now := c.getExp(0)
+	var misses []K
+	for k := range requests {
+		if entry, exists := c.cache[k]; exists && !entry.expired(now) {
+			if entry.missing {
+				errs[k] = ErrCachedMiss
+			} else {
+				values[k] = entry.value
+			}
+			continue
+		}
+		misses = append(misses, k)
+	}
+	c.mu.RUnlock()
+
+	for _, k := range misses {
+		fn := requests[k]
+		value, err := c.loadOnce(context.Background(), k, func(context.Context) (V, error) {
+			return fn()
+		}, optTTL)
+		if err != nil {
+			errs[k] = err
+			continue
+		}
+		values[k] = value
+	}
+	return values, errs
+}
+
+// LoadStream stores every entry received from in until it is closed, so a large dataset can be loaded without
+// holding it all in memory. An entry keeps its ExpiresAt, or gets the default TTL if ExpiresAt is zero, and one
+// that has already expired is skipped. An entry with Pinned set is loaded pinned. Each entry is stored under
+// its own lock, leaving the cache usable while the stream is slow. It stops at the first error Set would
+// return, or with ctx.Err() once ctx is done.
+func (c *TTLCache[K, V]) LoadStream(ctx context.Context, in <-chan Entry[K, V]) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case entry, ok := <-in:
+			if !ok {
+				return nil
+			}
+			if err := c.loadEntry(entry); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *TTLCache[K, V]) loadEntry(entry Entry[K, V]) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var exp int64
+	switch {
+	case entry.Pinned:
+		exp = neverExpires
+	case entry.ExpiresAt.IsZero():
+		exp = c.writeExp(nil)
+	default:
+		exp = entry.ExpiresAt.UnixNano()
+	}
+
+	return c.restore(entry.Key, entry.Value, exp, false)
+}
+
+// restore stores a previously saved entry, or tombstone if missing is set, with its absolute exp, skipping it if
+// it has expired since.
+func (c *TTLCache[K, V]) restore(key K, value V, exp int64, missing bool) error {
+	if exp < c.getExp(0) {
+		return nil
+	}
+	_, err := c.write(key, value, exp, missing, nil)
+	return err
+}
+
+// flight is a GetOrSet load in progress. Callers that miss the same key while it runs wait on done and share
+// its result.
+type flight[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// GetOrSet returns key's live value, or calls fn to load it and stores the result. Concurrent callers that
+// miss the same key share a single call to fn. fn runs without the cache's lock held, so other keys stay
+// usable while it runs and fn may use the cache itself. If fn returns an error nothing is stored, and every
+// caller waiting on that call gets the error. A SetMissing tombstone returns ErrCachedMiss without calling fn.
+func (c *TTLCache[K, V]) GetOrSet(key K, fn func() (V, error), optTTL ...time.Duration) (V, error) {
+	return c.GetOrSetCtx(context.Background(), key, func(context.Context) (V, error) {
+		return fn()
+	}, optTTL...)
+}
+
+// GetOrSetCtx is GetOrSet with a context: fn receives ctx, and a caller waiting on another caller's call to fn
+// gives up with ctx.Err() once ctx is done. fn receives the context of the caller that started the call, so if
+// that context is cancelled, every caller sharing the call gets fn's error. If fn panics, the panic reaches the
+// caller that started the call, the callers waiting on it get an error, and the next miss calls fn again.
+func (c *TTLCache[K, V]) GetOrSetCtx(ctx context.Context, key K, fn func(ctx context.Context) (V, error), optTTL ...time.Duration) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	if value, err := c.Get(key); err == nil || err == ErrCacheClosed || err == ErrCachedMiss {
+		return value, err
+	}
+	return c.loadOnce(ctx, key, fn, optTTL)
+}
+
+// loadOnce loads key with fn through its flight: it joins a load of key already in progress, or registers one
+// and runs load.
+func (c *TTLCache[K, V]) loadOnce(ctx context.Context, key K, fn func(ctx context.Context) (V, error), optTTL []time.Duration) (V, error) {
+	var zero V
+	c.flightsMu.Lock()
+	if f, inFlight := c.flights[key]; inFlight {
+		c.flightsMu.Unlock()
+		select {
+		case <-f.done:
+			return f.value, f.err
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+	//Until load returns, waiters see the error for a panicking fn
+	f := &flight[V]{done: make(chan struct{}), err: newLoaderPanicErr(key)}
+	c.flights[key] = f
+	c.flightsMu.Unlock()
+	defer func() {
+		c.flightsMu.Lock()
+		delete(c.flights, key)
+		c.flightsMu.Unlock()
+		close(f.done)
+	}()
+
+	f.value, f.err = c.load(ctx, key, fn, optTTL)
+	return f.value, f.err
+}
+
+// load runs fn for GetOrSetCtx and stores its result unless ctx is done by then. A flight that finished just
+// before this one registered has already stored its value, so that is used instead of calling fn again.
+func (c *TTLCache[K, V]) load(ctx context.Context, key K, fn func(ctx context.Context) (V, error), optTTL []time.Duration) (V, error) {
+	if value, err := c.Peek(key); err == nil || err == ErrCachedMiss {
+		return value, err
+	}
+
+	var zero V
+	value, err := fn(ctx)
+	if err != nil {
+		return zero, err
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	if _, err := c.set(key, value, c.writeExp(optTTL)); err != nil {
+		return zero, err
+	}
+	return value, nil
+}
@@ -0,0 +1,297 @@
+package ttl_cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Hits are served without running their loader
+// --Misses are loaded and stored
+// --A miss already being loaded by GetOrSet waits for that load
+//
+// -Error
+// --Failing loader is reported for its key only
+func TestCache_GetOrSetEach(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("hit"), "cached"))
+
+	loadErr := errors.New("backend down")
+	calls := map[key]int{}
+	loader := func(k key, value interface{}, err error) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			calls[k]++
+			return value, err
+		}
+	}
+
+	values, errs := cache.GetOrSetEach(map[key]func() (interface{}, error){
+		"hit":     loader("hit", "loaded", nil),
+		"miss":    loader("miss", "loaded", nil),
+		"failing": loader("failing", nil, loadErr),
+	}, 60*time.Second)
+
+	assert.Equal(t, map[key]interface{}{"hit": "cached", "miss": "loaded"}, values)
+	assert.Equal(t, map[key]error{"failing": loadErr}, errs)
+	assert.Equal(t, map[key]int{"miss": 1, "failing": 1}, calls)
+
+	assertKeyMapsToValue(t, "loaded", key("miss"), cache)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[key("miss")].exp)
+	assertKeyDoesNotExist(t, key("failing"), cache)
+}
+
+func TestCache_GetOrSetEach_SharesFlights(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithDefaultTTL(30 * time.Second))
+	require.Nil(t, err)
+
+	var calls int32
+	started := make(chan struct{})
+	loader := func() (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		//Give the other caller time to join the flight
+		time.Sleep(50 * time.Millisecond)
+		return "loaded", nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		value, err := cache.GetOrSet(key("shared"), loader)
+		assert.Nil(t, err)
+		assert.Equal(t, "loaded", value)
+	}()
+	<-started
+	values, errs := cache.GetOrSetEach(map[key]func() (interface{}, error){"shared": loader})
+	<-done
+
+	assert.Empty(t, errs)
+	assert.Equal(t, map[key]interface{}{"shared": "loaded"}, values)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+// TestCases
+// -Success
+// --Hit returns the cached value without calling fn
+// --Miss stores fn's value with the provided TTL
+// --Concurrent misses share one call to fn
+//
+// -Error
+// --fn's error is returned and nothing is stored
+func TestCache_GetOrSet(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("hit"), "cached"))
+	fail := func() (interface{}, error) {
+		t.Fatal("fn called on a hit")
+		return nil, nil
+	}
+
+	value, err := cache.GetOrSet(key("hit"), fail)
+	assert.Nil(t, err)
+	assert.Equal(t, "cached", value)
+
+	value, err = cache.GetOrSet(key("miss"), func() (interface{}, error) { return "loaded", nil }, 60*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "loaded", value)
+	assertKeyMapsToValue(t, "loaded", key("miss"), cache)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[key("miss")].exp)
+
+	loadErr := errors.New("backend down")
+	value, err = cache.GetOrSet(key("failing"), func() (interface{}, error) { return "partial", loadErr })
+	assert.Equal(t, loadErr, err)
+	assert.Nil(t, value)
+	assertKeyDoesNotExist(t, key("failing"), cache)
+}
+
+func TestCache_GetOrSet_Concurrent(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	var calls int32
+	fn := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return "loaded", nil
+	}
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			value, err := cache.GetOrSet(key("shared"), fn)
+			assert.Nil(t, err)
+			assert.Equal(t, "loaded", value)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assertKeyMapsToValue(t, "loaded", key("shared"), cache)
+}
+
+// TestCases
+// -Success
+// --The next GetOrSet after a panic calls fn again
+//
+// -Error
+// --The panic reaches the caller that ran fn
+// --A waiter on the panicking call gets an error instead of blocking
+func TestCache_GetOrSet_Panic(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithDefaultTTL(30 * time.Second))
+	require.Nil(t, err)
+
+	started := make(chan struct{})
+	waited := make(chan error)
+	go func() {
+		<-started
+		_, err := cache.GetOrSet(key("k"), func() (interface{}, error) {
+			t.Error("waiter ran fn")
+			return nil, nil
+		})
+		waited <- err
+	}()
+
+	assert.PanicsWithValue(t, "loader broke", func() {
+		_, _ = cache.GetOrSet(key("k"), func() (interface{}, error) {
+			close(started)
+			//Give the waiter time to join the flight
+			time.Sleep(50 * time.Millisecond)
+			panic("loader broke")
+		})
+	})
+	select {
+	case err := <-waited:
+		assert.Equal(t, newLoaderPanicErr(key("k")), err)
+	case <-time.After(time.Second):
+		t.Fatal("waiter blocked after the loader panicked")
+	}
+
+	value, err := cache.GetOrSet(key("k"), func() (interface{}, error) { return "loaded", nil })
+	assert.Nil(t, err)
+	assert.Equal(t, "loaded", value)
+	assert.Empty(t, cache.flights)
+}
+
+// TestCases
+// -Success
+// --fn receives the caller's context
+//
+// -Error
+// --Cancelled context returns ctx.Err() without calling fn
+// --Context cancelled while fn runs stores nothing
+// --Waiter gives up when its own context is cancelled
+func TestCache_GetOrSetCtx(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithDefaultTTL(30 * time.Second))
+	require.Nil(t, err)
+	type ctxKey struct{}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	value, err := cache.GetOrSetCtx(ctx, key("loaded"), func(ctx context.Context) (interface{}, error) {
+		return ctx.Value(ctxKey{}), nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "request", value)
+	assertKeyMapsToValue(t, "request", key("loaded"), cache)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	value, err = cache.GetOrSetCtx(cancelled, key("cancelled"), func(context.Context) (interface{}, error) {
+		t.Fatal("fn called with a cancelled context")
+		return nil, nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, value)
+	assertKeyDoesNotExist(t, key("cancelled"), cache)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	_, err = cache.GetOrSetCtx(ctx, key("cancelled"), func(context.Context) (interface{}, error) {
+		cancel()
+		return "late", nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assertKeyDoesNotExist(t, key("cancelled"), cache)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	go func() {
+		_, _ = cache.GetOrSet(key("slow"), func() (interface{}, error) {
+			close(started)
+			<-release
+			return "slow", nil
+		})
+	}()
+	<-started
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = cache.GetOrSetCtx(ctx, key("slow"), func(context.Context) (interface{}, error) {
+		t.Fatal("fn called while another call was in flight")
+		return nil, nil
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	close(release)
+}
+
+// TestCases
+// -Success
+// --Every entry is stored with its expiry until the channel closes
+// --An entry without an expiry gets the default TTL
+// --A pinned entry is loaded pinned
+// --Expired entries are skipped
+//
+// -Error
+// --Cancelling the context stops the load mid-stream
+func TestCache_LoadStream(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	expiresAt := time.Now().Add(time.Minute)
+	in := make(chan Entry[key, interface{}], 5)
+	in <- Entry[key, interface{}]{Key: "first", Value: 1, ExpiresAt: expiresAt}
+	in <- Entry[key, interface{}]{Key: "second", Value: 2, ExpiresAt: expiresAt}
+	in <- Entry[key, interface{}]{Key: "row", Value: 3}
+	in <- Entry[key, interface{}]{Key: "pinned", Value: 4, Pinned: true}
+	in <- Entry[key, interface{}]{Key: "stale", Value: 5, ExpiresAt: time.Now().Add(-time.Minute)}
+	close(in)
+
+	require.Nil(t, cache.LoadStream(context.Background(), in))
+	assertCacheHasNKeys(t, 4, cache)
+	assertKeyMapsToValue(t, 1, key("first"), cache)
+	assertKeyMapsToValue(t, 2, key("second"), cache)
+	assert.Equal(t, expiresAt.UnixNano(), cache.cache[key("first")].exp)
+	assertExpNear(t, getExp(30*time.Second), cache.cache[key("row")].exp)
+	assert.True(t, cache.cache[key("pinned")].pinned())
+	assertKeyDoesNotExist(t, key("stale"), cache)
+}
+
+func TestCache_LoadStream_Cancelled(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan Entry[key, interface{}])
+	done := make(chan error)
+	go func() {
+		done <- cache.LoadStream(ctx, in)
+	}()
+
+	in <- Entry[key, interface{}]{Key: "loaded", Value: "value"}
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	assertCacheHasNKeys(t, 1, cache)
+	assertKeyMapsToValue(t, "value", key("loaded"), cache)
+}
@@ -0,0 +1,275 @@
+package ttl_cache
+
+import (
+	"strings"
+	"time"
+)
+
+// Migrate replaces key's value with the result of migrate when it reports changed, leaving the expiry
+// untouched. An error from migrate is returned as-is and the entry is left unchanged. migrate runs with the
+// cache locked and must not call back into it. A change counts as an overwrite.
+func (c *TTLCache[K, V]) Migrate(key K, migrate func(old V) (new V, changed bool, err error)) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entry, err := c.lookup(key)
+	if err != nil {
+		return err
+	}
+
+	migrated, changed, err := migrate(entry.value)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	cost := c.cost(migrated)
+	if err := c.reserveBytes(key, cost, entry.cost, entry); err != nil {
+		return err
+	}
+
+	entry.value = migrated
+	c.charge(entry, cost)
+	entry.version++
+	c.stats.recordSet()
+	c.queueEvent(key, ChangeSet, migrated)
+	return nil
+}
+
+// CopyKey stores srcKey's value under dstKey with the same expiry. An existing dstKey is overwritten as if by
+// Set. The copy is independent: it does not inherit srcKey's parent. A SetMissing tombstone is copied as a
+// tombstone.
+func (c *TTLCache[K, V]) CopyKey(srcKey, dstKey K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	src, exists := c.cache[srcKey]
+	if !exists || src.expired(c.getExp(0)) {
+		return newKeyNotFoundErr(srcKey)
+	}
+	if srcKey == dstKey {
+		return nil
+	}
+
+	_, err := c.write(dstKey, src.value, src.exp, src.missing, nil)
+	return err
+}
+
+// Compute replaces key's entry with the result of fn, which receives the current live value if there is one.
+// If keep is false the entry is removed; otherwise newValue is stored with ttl, or the default TTL if ttl is
+// not positive, and a ttl of NoExpiry stores it pinned. A SetMissing tombstone holds no value, so fn is told
+// nothing was found, and the tombstone is replaced or removed like a live entry. fn runs with the cache locked
+// and must not call back into it.
+func (c *TTLCache[K, V]) Compute(key K, fn func(old V, found bool) (newValue V, ttl time.Duration, keep bool)) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var old V
+	entry, found := c.cache[key]
+	if found && entry.expired(c.getExp(0)) {
+		c.removeEntry(entry, ChangeExpire)
+		found = false
+	}
+	if found && !entry.missing {
+		old = entry.value
+	}
+
+	newValue, ttl, keep := fn(old, found && !entry.missing)
+	if !keep {
+		if found {
+			c.removeEntry(entry, ChangeDelete)
+		}
+		return nil
+	}
+
+	_, err := c.set(key, newValue, c.writeExp([]time.Duration{ttl}))
+	return err
+}
+
+// DeleteOlderThan removes every live entry first stored more than age ago, regardless of its TTL, and returns
+// how many it removed. Children of a removed entry are removed with it, however young, and counted too.
+func (c *TTLCache[K, V]) DeleteOlderThan(age time.Duration) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.getExp(0)
+	cutoff := c.getExp(-age)
+	var old []*cacheEntry[K, V]
+	for _, entry := range c.ttlHK {
+		if entry.createdAt < cutoff && !entry.expired(now) {
+			old = append(old, entry)
+		}
+	}
+
+	before := len(c.cache)
+	for _, entry := range old {
+		if c.cache[entry.key] == entry {
+			c.removeEntry(entry, ChangeDelete)
+		}
+	}
+	return before - len(c.cache)
+}
+
+// DeleteIf removes key only if pred holds for its current value, and reports whether it did. pred runs with
+// the cache locked and must not call back into it. A SetMissing tombstone returns ErrCachedMiss.
+func (c *TTLCache[K, V]) DeleteIf(key K, pred func(value V) bool) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entry, err := c.lookup(key)
+	if err != nil {
+		return false, err
+	}
+	if !pred(entry.value) {
+		return false, nil
+	}
+
+	c.removeEntry(entry, ChangeDelete)
+	return true, nil
+}
+
+// TouchPrefix resets the expiry of every live, unpinned entry whose key starts with prefix to the default TTL,
+// or the provided one, and returns how many entries it touched. Only string keys can match a prefix.
+func (c *TTLCache[K, V]) TouchPrefix(prefix string, optTTL ...time.Duration) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.getExp(0)
+	var touched []*cacheEntry[K, V]
+	for _, entry := range c.ttlHK {
+		if entry.expired(now) || entry.pinned() {
+			continue
+		}
+		if s, ok := keyString(entry.key); ok && strings.HasPrefix(s, prefix) {
+			entry.exp = c.writeExp(optTTL)
+			entry.writtenAt = now
+			touched = append(touched, entry)
+		}
+	}
+	if len(touched) == 0 {
+		return 0
+	}
+	c.rebuildIndex()
+	//Re-apply the SetChild caps now that ttlHK is sorted again
+	for _, entry := range touched {
+		if entry.hasParent || len(c.children[entry.key]) > 0 {
+			c.moveHKEntry(entry, entry.exp)
+		}
+	}
+	return len(touched)
+}
+
+// Touch resets key's expiry to the default TTL, or the provided one, from now, leaving its value untouched.
+// A pinned entry stays pinned.
+func (c *TTLCache[K, V]) Touch(key K, optTTL ...time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.cache[key]
+	if !exists || entry.expired(c.getExp(0)) {
+		return newKeyNotFoundErr(key)
+	}
+	if entry.pinned() {
+		return nil
+	}
+	c.moveHKEntry(entry, c.writeExp(optTTL))
+	return nil
+}
+
+// ExtendOnly moves key's expiry to now+ttl only if that is later than its current expiry, and reports whether
+// it did. A pinned entry is never extended, and a SetChild child is never extended past its parent.
+func (c *TTLCache[K, V]) ExtendOnly(key K, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.cache[key]
+	if !exists || entry.expired(c.getExp(0)) {
+		return false, newKeyNotFoundErr(key)
+	}
+
+	exp := c.capToParent(entry, c.getExp(ttl))
+	if exp <= entry.exp {
+		return false, nil
+	}
+	c.moveHKEntry(entry, exp)
+	return true, nil
+}
+
+// PopMany removes every live entry among keys, returning their values and the keys that were missing. A
+// SetMissing tombstone is removed and its key reported as missing.
+func (c *TTLCache[K, V]) PopMany(keys []K) (map[K]V, []K) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.getExp(0)
+	values := make(map[K]V, len(keys))
+	var missing []K
+	for _, k := range keys {
+		entry, exists := c.cache[k]
+		if !exists || entry.expired(now) {
+			if _, popped := values[k]; !popped {
+				missing = append(missing, k)
+			}
+			continue
+		}
+		if entry.missing {
+			missing = append(missing, k)
+		} else {
+			values[k] = entry.value
+		}
+		c.removeEntry(entry, ChangeDelete)
+	}
+	return values, missing
+}
+
+// SwapKeys exchanges the values of a and b in one operation. Each key keeps its own expiry, and both count as
+// overwritten.
+func (c *TTLCache[K, V]) SwapKeys(a, b K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entryA, err := c.lookup(a)
+	if err != nil {
+		return err
+	}
+	entryB, err := c.lookup(b)
+	if err != nil {
+		return err
+	}
+	if a == b {
+		return nil
+	}
+
+	entryA.value, entryB.value = entryB.value, entryA.value
+	entryA.cost, entryB.cost = entryB.cost, entryA.cost
+	for _, entry := range []*cacheEntry[K, V]{entryA, entryB} {
+		entry.version++
+		c.stats.recordSet()
+		c.queueEvent(entry.key, ChangeSet, entry.value)
+	}
+	return nil
+}
+
+// Update replaces key's value without restarting its clock: the expiry and the entry's place in ttlHK are left
+// untouched, and a pinned entry stays pinned. It counts as an overwrite.
+func (c *TTLCache[K, V]) Update(key K, value V) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entry, err := c.lookup(key)
+	if err != nil {
+		return err
+	}
+	cost := c.cost(value)
+	if err := c.reserveBytes(key, cost, entry.cost, entry); err != nil {
+		return err
+	}
+
+	entry.value = value
+	c.charge(entry, cost)
+	entry.version++
+	c.stats.recordSet()
+	c.queueEvent(key, ChangeSet, value)
+	return nil
+}
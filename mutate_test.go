@@ -0,0 +1,431 @@
+package ttl_cache
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// TestCases
+// -Success
+// --Old format value is migrated
+// --Already migrated value is skipped
+//
+// -Error
+// --Migration error leaves the value unchanged
+// --Missing key
+func TestCache_Migrate(t *testing.T) {
+	mc := new(migrateSuite)
+	suite.Run(t, mc)
+}
+
+type migrateSuite struct {
+	cacheSuite
+}
+
+func (mc *migrateSuite) SetupTest() {
+	mc.cacheSuite.SetupSuite()
+}
+
+// toInt migrates string values to ints
+func toInt(old interface{}) (interface{}, bool, error) {
+	s, isString := old.(string)
+	if !isString {
+		return old, false, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, false, err
+	}
+	return n, true, nil
+}
+
+func (mc *migrateSuite) TestMigrate_OldFormat() {
+	k := key("old")
+	require.Nil(mc.T(), mc.cache.Set(k, "42"))
+	exp := mc.cache.cache[k].exp
+
+	err := mc.cache.Migrate(k, toInt)
+	assert.Nil(mc.T(), err)
+	assertKeyMapsToValue(mc.T(), 42, k, mc.cache)
+	assert.Equal(mc.T(), exp, mc.cache.cache[k].exp)
+	assert.Equal(mc.T(), uint64(2), mc.cache.cache[k].version)
+}
+
+func (mc *migrateSuite) TestMigrate_AlreadyMigrated() {
+	k := key("new")
+	require.Nil(mc.T(), mc.cache.Set(k, 42))
+
+	err := mc.cache.Migrate(k, toInt)
+	assert.Nil(mc.T(), err)
+	assertKeyMapsToValue(mc.T(), 42, k, mc.cache)
+	assert.Equal(mc.T(), uint64(1), mc.cache.cache[k].version)
+}
+
+func (mc *migrateSuite) TestMigrate_Error() {
+	k := key("bad")
+	require.Nil(mc.T(), mc.cache.Set(k, "not a number"))
+
+	err := mc.cache.Migrate(k, toInt)
+	var numErr *strconv.NumError
+	assert.True(mc.T(), errors.As(err, &numErr))
+	assertKeyMapsToValue(mc.T(), "not a number", k, mc.cache)
+}
+
+func (mc *migrateSuite) TestMigrate_MissingKey() {
+	err := mc.cache.Migrate(key("missing"), toInt)
+	assert.Equal(mc.T(), newKeyNotFoundErr(key("missing")), err)
+}
+
+// TestCases
+// -Success
+// --Copy to a new key
+// --Copy overwrites an existing destination
+//
+// -Error
+// --Missing source
+func TestCache_CopyKey(t *testing.T) {
+	cc := new(copyKeySuite)
+	suite.Run(t, cc)
+}
+
+type copyKeySuite struct {
+	cacheSuite
+}
+
+func (cc *copyKeySuite) SetupTest() {
+	cc.cacheSuite.SetupSuite()
+	require.Nil(cc.T(), cc.cache.Set(key("src"), "value", 60*time.Second))
+}
+
+func (cc *copyKeySuite) TestCopyKey_NewKey() {
+	err := cc.cache.CopyKey(key("src"), key("dst"))
+	assert.Nil(cc.T(), err)
+
+	assertKeyMapsToValue(cc.T(), "value", key("src"), cc.cache)
+	assertKeyMapsToValue(cc.T(), "value", key("dst"), cc.cache)
+	assert.Equal(cc.T(), cc.cache.cache[key("src")].exp, cc.cache.cache[key("dst")].exp)
+	assertCacheHasNKeys(cc.T(), 2, cc.cache)
+}
+
+func (cc *copyKeySuite) TestCopyKey_OverwritesDestination() {
+	require.Nil(cc.T(), cc.cache.Set(key("dst"), "old", 5*time.Second))
+
+	err := cc.cache.CopyKey(key("src"), key("dst"))
+	assert.Nil(cc.T(), err)
+
+	assertKeyMapsToValue(cc.T(), "value", key("dst"), cc.cache)
+	assert.Equal(cc.T(), cc.cache.cache[key("src")].exp, cc.cache.cache[key("dst")].exp)
+	assertCacheHasNKeys(cc.T(), 2, cc.cache)
+}
+
+func (cc *copyKeySuite) TestCopyKey_MissingSource() {
+	err := cc.cache.CopyKey(key("missing"), key("dst"))
+	assert.Equal(cc.T(), newKeyNotFoundErr(key("missing")), err)
+	assertKeyDoesNotExist(cc.T(), key("dst"), cc.cache)
+}
+
+// TestCases
+// -Success
+// --Missing key is created
+// --Existing key is updated with a new TTL
+// --keep=false deletes the entry
+// --keep=false on a missing key is a no-op
+func TestCache_Compute(t *testing.T) {
+	cc := new(computeSuite)
+	suite.Run(t, cc)
+}
+
+type computeSuite struct {
+	cacheSuite
+}
+
+func (cc *computeSuite) SetupTest() {
+	cc.cacheSuite.SetupSuite()
+}
+
+func increment(old interface{}, found bool) (interface{}, time.Duration, bool) {
+	if !found {
+		return 1, 0, true
+	}
+	return old.(int) + 1, 60 * time.Second, true
+}
+
+func (cc *computeSuite) TestCompute_Create() {
+	k := key("counter")
+	err := cc.cache.Compute(k, increment)
+	assert.Nil(cc.T(), err)
+
+	assertKeyMapsToValue(cc.T(), 1, k, cc.cache)
+	assertExpNear(cc.T(), getExp(cc.defaultTTL), cc.cache.cache[k].exp)
+}
+
+func (cc *computeSuite) TestCompute_UpdateWithNewTTL() {
+	k := key("counter")
+	require.Nil(cc.T(), cc.cache.Set(k, 1))
+
+	err := cc.cache.Compute(k, increment)
+	assert.Nil(cc.T(), err)
+
+	assertKeyMapsToValue(cc.T(), 2, k, cc.cache)
+	assertExpNear(cc.T(), getExp(60*time.Second), cc.cache.cache[k].exp)
+	assertCacheHasNKeys(cc.T(), 1, cc.cache)
+}
+
+func (cc *computeSuite) TestCompute_Delete() {
+	k := key("counter")
+	require.Nil(cc.T(), cc.cache.Set(k, 1))
+
+	err := cc.cache.Compute(k, func(old interface{}, found bool) (interface{}, time.Duration, bool) {
+		assert.True(cc.T(), found)
+		assert.Equal(cc.T(), 1, old)
+		return nil, 0, false
+	})
+	assert.Nil(cc.T(), err)
+
+	assertKeyDoesNotExist(cc.T(), k, cc.cache)
+	assertCacheHasNKeys(cc.T(), 0, cc.cache)
+}
+
+func (cc *computeSuite) TestCompute_DeleteMissing() {
+	err := cc.cache.Compute(key("missing"), func(old interface{}, found bool) (interface{}, time.Duration, bool) {
+		assert.False(cc.T(), found)
+		assert.Nil(cc.T(), old)
+		return nil, 0, false
+	})
+	assert.Nil(cc.T(), err)
+	assertCacheHasNKeys(cc.T(), 0, cc.cache)
+}
+
+// TestCases
+// -Success
+// --Only entries older than the cutoff are removed
+// --Nothing old enough removes nothing
+// --Young children removed with an old parent are counted
+func TestCache_DeleteOlderThan(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	ages := map[key]time.Duration{
+		"fresh":  0,
+		"recent": 5 * time.Second,
+		"old":    20 * time.Second,
+		"oldest": 60 * time.Second,
+	}
+	for k, age := range ages {
+		require.Nil(t, cache.Set(k, "value"))
+		cache.cache[k].createdAt = getExp(-age)
+	}
+
+	assert.Equal(t, 0, cache.DeleteOlderThan(2*time.Minute))
+	assert.Equal(t, 2, cache.DeleteOlderThan(10*time.Second))
+
+	assertKeyMapsToValue(t, "value", key("fresh"), cache)
+	assertKeyMapsToValue(t, "value", key("recent"), cache)
+	assertKeyDoesNotExist(t, key("old"), cache)
+	assertKeyDoesNotExist(t, key("oldest"), cache)
+	assert.Nil(t, cache.checkInvariants())
+
+	require.Nil(t, cache.Set(key("parent"), "value"))
+	cache.cache[key("parent")].createdAt = getExp(-time.Minute)
+	require.Nil(t, cache.SetChild(key("parent"), key("child"), "value"))
+	assert.Equal(t, 2, cache.DeleteOlderThan(10*time.Second))
+	assertKeyDoesNotExist(t, key("child"), cache)
+	assertCacheHasNKeys(t, 2, cache)
+}
+
+// TestCases
+// -Success
+// --Predicate true deletes
+// --Predicate false keeps
+//
+// -Error
+// --Missing key
+func TestCache_DeleteIf(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("done"), "done"))
+	require.Nil(t, cache.Set(key("running"), "running"))
+
+	isDone := func(value interface{}) bool {
+		return value == "done"
+	}
+
+	deleted, err := cache.DeleteIf(key("done"), isDone)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	assertKeyDoesNotExist(t, key("done"), cache)
+
+	deleted, err = cache.DeleteIf(key("running"), isDone)
+	assert.Nil(t, err)
+	assert.False(t, deleted)
+	assertKeyMapsToValue(t, "running", key("running"), cache)
+
+	deleted, err = cache.DeleteIf(key("missing"), isDone)
+	assert.Equal(t, newKeyNotFoundErr(key("missing")), err)
+	assert.False(t, deleted)
+	assertCacheHasNKeys(t, 1, cache)
+}
+
+// TestCases
+// -Success
+// --Only keys with the prefix are touched and ttlHK stays sorted
+// --No matching keys touches nothing
+func TestCache_TouchPrefix(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("tenant1:a"), "a", 5*time.Second))
+	require.Nil(t, cache.Set(key("tenant2:a"), "a", 20*time.Second))
+	require.Nil(t, cache.Set(key("tenant1:b"), "b", 10*time.Second))
+
+	assert.Equal(t, 0, cache.TouchPrefix("tenant3:"))
+
+	touched := cache.TouchPrefix("tenant1:", 60*time.Second)
+	assert.Equal(t, 2, touched)
+
+	assertExpNear(t, getExp(60*time.Second), cache.cache[key("tenant1:a")].exp)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[key("tenant1:b")].exp)
+	assertExpNear(t, getExp(20*time.Second), cache.cache[key("tenant2:a")].exp)
+	assert.Equal(t, key("tenant2:a"), cache.ttlHK[0].key)
+	assert.Nil(t, cache.checkInvariants())
+}
+
+// TestCases
+// -Success
+// --Touched entry survives past its original expiry with its value unchanged
+// --Pinned entry stays pinned
+//
+// -Error
+// --Missing key
+func TestCache_Touch(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	k := key("session")
+	require.Nil(t, cache.Set(k, "user", time.Second))
+	require.Nil(t, cache.Set(key("other"), "value", 10*time.Second))
+	require.Nil(t, cache.Set(key("pinned"), "value"))
+	require.Nil(t, cache.Pin(key("pinned")))
+
+	assert.Nil(t, cache.Touch(k, 60*time.Second))
+	assertExpNear(t, getExp(60*time.Second), cache.cache[k].exp)
+	assert.Nil(t, cache.checkInvariants())
+
+	//Sweep as if the original TTL had passed
+	cache.evict(getExp(2 * time.Second))
+	assertKeyMapsToValue(t, "user", k, cache)
+
+	assert.Nil(t, cache.Touch(key("pinned")))
+	assert.True(t, cache.cache[key("pinned")].pinned())
+
+	assert.Equal(t, newKeyNotFoundErr(key("missing")), cache.Touch(key("missing")))
+}
+
+// TestCases
+// -Success
+// --Longer TTL extends
+// --Shorter TTL is a no-op
+//
+// -Error
+// --Missing key
+func TestCache_ExtendOnly(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	k := key("lease")
+	require.Nil(t, cache.Set(k, "holder"))
+	require.Nil(t, cache.Set(key("other"), "value", 45*time.Second))
+
+	extended, err := cache.ExtendOnly(k, 60*time.Second)
+	assert.Nil(t, err)
+	assert.True(t, extended)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[k].exp)
+	assert.Nil(t, cache.checkInvariants())
+
+	extended, err = cache.ExtendOnly(k, 10*time.Second)
+	assert.Nil(t, err)
+	assert.False(t, extended)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[k].exp)
+
+	extended, err = cache.ExtendOnly(key("missing"), 60*time.Second)
+	assert.Equal(t, newKeyNotFoundErr(key("missing")), err)
+	assert.False(t, extended)
+}
+
+// TestCases
+// -Success
+// --Present keys are returned and deleted, absent keys reported
+// --Repeated key is popped once
+func TestCache_PopMany(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("token1"), 1))
+	require.Nil(t, cache.Set(key("token2"), 2))
+	require.Nil(t, cache.Set(key("kept"), 3))
+
+	values, missing := cache.PopMany([]key{"token1", "absent", "token2", "token1"})
+
+	assert.Equal(t, map[key]interface{}{"token1": 1, "token2": 2}, values)
+	assert.Equal(t, []key{"absent"}, missing)
+	assertKeyDoesNotExist(t, key("token1"), cache)
+	assertKeyDoesNotExist(t, key("token2"), cache)
+	assertKeyMapsToValue(t, 3, key("kept"), cache)
+	assertCacheHasNKeys(t, 1, cache)
+}
+
+// TestCases
+// -Success
+// --Values are swapped and each key keeps its expiry
+//
+// -Error
+// --Missing key leaves both entries unchanged
+func TestCache_SwapKeys(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("active"), "blue", 10*time.Second))
+	require.Nil(t, cache.Set(key("standby"), "green", 60*time.Second))
+
+	assert.Nil(t, cache.SwapKeys(key("active"), key("standby")))
+	assertKeyMapsToValue(t, "green", key("active"), cache)
+	assertKeyMapsToValue(t, "blue", key("standby"), cache)
+	assertExpNear(t, getExp(10*time.Second), cache.cache[key("active")].exp)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[key("standby")].exp)
+
+	err = cache.SwapKeys(key("active"), key("missing"))
+	assert.Equal(t, newKeyNotFoundErr(key("missing")), err)
+	assertKeyMapsToValue(t, "green", key("active"), cache)
+	assertKeyMapsToValue(t, "blue", key("standby"), cache)
+}
+
+// TestCases
+// -Success
+// --Value changes while the expiry and ttlHK order stay identical
+//
+// -Error
+// --Missing key
+// --Expired key
+func TestCache_Update(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("first"), "first", 10*time.Second))
+	require.Nil(t, cache.Set(key("price"), 100, 20*time.Second))
+	require.Nil(t, cache.Set(key("last"), "last", 60*time.Second))
+	require.Nil(t, cache.Set(key("expired"), "expired"))
+	cache.moveHKEntry(cache.cache[key("expired")], getExp(-time.Second))
+	exp := cache.cache[key("price")].exp
+	order := entryKeys(cache.EntriesSnapshot())
+
+	assert.Nil(t, cache.Update(key("price"), 90))
+	assertKeyMapsToValue(t, 90, key("price"), cache)
+	assert.Equal(t, exp, cache.cache[key("price")].exp)
+	assert.Equal(t, uint64(2), cache.cache[key("price")].version)
+	assert.Equal(t, order, entryKeys(cache.EntriesSnapshot()))
+
+	for _, k := range []key{"missing", "expired"} {
+		assert.Equal(t, newKeyNotFoundErr(k), cache.Update(k, 0))
+	}
+	assertKeyDoesNotExist(t, key("missing"), cache)
+}
@@ -0,0 +1,86 @@
+package ttl_cache
+
+import (
+	"fmt"
+	"time"
+)
+
+type OpKind uint8
+
+const (
+	OpSet OpKind = iota
+	OpGet
+	OpEvict
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpSet:
+		return "set"
+	case OpGet:
+		return "get"
+	case OpEvict:
+		return "evict"
+	}
+	return "unknown"
+}
+
+// Operation is a single step replayed by FuzzOperations. TTL is passed to Set as its optTTL; for OpEvict it
+// is the offset from now used as the eviction cutoff.
+type Operation[K comparable, V any] struct {
+	Kind  OpKind
+	Key   K
+	Value V
+	TTL   time.Duration
+}
+
+// FuzzOperations applies ops to c in order and checks the cache's internal invariants after every step,
+// panicking with the offending step if one is violated. It lets a failing sequence found by `go test -fuzz`
+// be replayed and minimized outside the fuzzer.
+func FuzzOperations[K comparable, V any](c *TTLCache[K, V], ops []Operation[K, V]) {
+	for i, op := range ops {
+		switch op.Kind {
+		case OpSet:
+			_ = c.Set(op.Key, op.Value, op.TTL)
+		case OpGet:
+			_, _ = c.Get(op.Key)
+		case OpEvict:
+			c.mu.Lock()
+			c.evict(c.getExp(op.TTL))
+			c.unlock()
+		}
+
+		c.mu.RLock()
+		err := c.checkInvariants()
+		c.mu.RUnlock()
+		if err != nil {
+			panic(fmt.Sprintf("operation %d (%s %v): %s", i, op.Kind, op.Key, err))
+		}
+	}
+}
+
+// checkInvariants verifies that the map and ttlHK hold the same entries, that ttlHK is sorted by ascending exp,
+// and that the recorded byte usage is the sum of the entries' costs.
+func (c *TTLCache[K, V]) checkInvariants() error {
+	if len(c.cache) != len(c.ttlHK) {
+		return newIndexLenMismatchErr(len(c.cache), len(c.ttlHK))
+	}
+
+	seen := make(map[K]struct{}, len(c.ttlHK))
+	var bytes int64
+	for i, entry := range c.ttlHK {
+		bytes += entry.cost
+		if _, dup := seen[entry.key]; dup || c.cache[entry.key] != entry {
+			return newIndexEntryErr(entry.key, i)
+		}
+		seen[entry.key] = struct{}{}
+
+		if i > 0 && c.ttlHK[i-1].exp > entry.exp {
+			return newIndexOrderErr(i)
+		}
+	}
+	if recorded := c.stats.bytesUsed(); bytes != recorded {
+		return newBytesMismatchErr(bytes, recorded)
+	}
+	return nil
+}
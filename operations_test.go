@@ -0,0 +1,72 @@
+package ttl_cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Valid sequence applies every operation
+//
+// -Error
+// --Corrupted index panics with the failing step
+func TestFuzzOperations(t *testing.T) {
+	t.Run("valid sequence", func(t *testing.T) {
+		cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+		require.Nil(t, err)
+
+		ops := []Operation[key, interface{}]{
+			{Kind: OpSet, Key: key("a"), Value: 1},
+			{Kind: OpSet, Key: key("b"), Value: 2, TTL: 60 * time.Second},
+			{Kind: OpGet, Key: key("a")},
+			{Kind: OpEvict, TTL: 45 * time.Second},
+		}
+		assert.NotPanics(t, func() {
+			FuzzOperations(cache, ops)
+		})
+		assertKeyDoesNotExist(t, key("a"), cache)
+		assertKeyMapsToValue(t, 2, key("b"), cache)
+	})
+
+	t.Run("corrupted index", func(t *testing.T) {
+		cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+		require.Nil(t, err)
+		require.Nil(t, cache.Set(key("a"), 1))
+		cache.ttlHK = append(cache.ttlHK, newCacheEntry[key, interface{}](key("orphan"), 2, 0))
+
+		assert.PanicsWithValue(t, "operation 0 (get a): "+newIndexLenMismatchErr(1, 2).Error(), func() {
+			FuzzOperations(cache, []Operation[key, interface{}]{{Kind: OpGet, Key: key("a")}})
+		})
+	})
+}
+
+func FuzzTTLCache_Operations(f *testing.F) {
+	f.Add([]byte{0, 0, 0, 0, 1, 2, 1, 0, 0, 2, 0, 3})
+	f.Add([]byte{0, 1, 1, 0, 2, 2, 0, 3, 3, 2, 0, 2, 1, 3, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		//OpEvict drives eviction, so a sweeper would only leak a goroutine per iteration
+		cache, err := NewTTLCache[key, interface{}](WithSize(16), WithDefaultTTL(30*time.Second), WithNoSweeper())
+		require.Nil(t, err)
+		FuzzOperations(cache, decodeOperations(data))
+	})
+}
+
+// decodeOperations turns fuzz input into operations, three bytes per step: kind, key and TTL in seconds.
+func decodeOperations(data []byte) []Operation[key, interface{}] {
+	ops := make([]Operation[key, interface{}], 0, len(data)/3)
+	for i := 0; i+2 < len(data); i += 3 {
+		ops = append(ops, Operation[key, interface{}]{
+			Kind:  OpKind(data[i] % 3),
+			Key:   key(fmt.Sprintf("k%d", data[i+1]%8)),
+			Value: int(data[i+1]),
+			TTL:   time.Duration(data[i+2]%120) * time.Second,
+		})
+	}
+	return ops
+}
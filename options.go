@@ -0,0 +1,205 @@
+package ttl_cache
+
+import "time"
+
+// The configuration NewTTLCache uses when it isn't given WithSize, WithDefaultTTL or WithSweepPeriod.
+const (
+	DefaultSize        = 1024
+	DefaultTTL         = 5 * time.Minute
+	DefaultSweepPeriod = time.Minute
+)
+
+// Option configures a cache at construction. Options are independent of the cache's key and value types, so
+// the same Option works with any NewTTLCache instantiation.
+type Option func(*options)
+
+// options holds the settings Options write; TTLCache embeds it.
+type options struct {
+	size         uint
+	defaultTTL   time.Duration
+	sweepPeriod  time.Duration
+	noSweeper    bool
+	maxKeyLen    int
+	pressureFn   func() float64
+	topKSize     int
+	equalFn      func(a, b interface{}) bool
+	selfHealing  bool
+	cleanupOnGet bool
+	slidingTTL   bool
+	eviction     EvictionPolicy
+	monotonic    bool
+	entryPooling bool
+	tracer       Tracer
+	onEvict      func(key, value interface{})
+	onExpire     func(key, value interface{})
+	maxJitter    time.Duration
+	maxBytes     int64
+	sizer        func(value interface{}) int
+}
+
+// WithSize sets how many entries the cache holds before Set has to evict one. It must be greater than 0.
+func WithSize(n uint) Option {
+	return func(o *options) {
+		o.size = n
+	}
+}
+
+// WithDefaultTTL sets the TTL of entries stored without one. It must be greater than 0, or NoExpiry.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.defaultTTL = ttl
+	}
+}
+
+// WithSweepPeriod sets how often the background sweep removes expired entries. It must be greater than 0.
+func WithSweepPeriod(period time.Duration) Option {
+	return func(o *options) {
+		o.sweepPeriod = period
+	}
+}
+
+// WithNoSweeper creates the cache without a background sweep, so no ticker or goroutine is started. Expired
+// entries are still never returned, but they keep their slots until SweepTick, TriggerSweep or Set's evictions
+// remove them. Any WithSweepPeriod is ignored.
+func WithNoSweeper() Option {
+	return func(o *options) {
+		o.noSweeper = true
+	}
+}
+
+// WithExternalSweep is WithNoSweeper for a host that drives sweeps itself, calling SweepTick on its own
+// schedule, e.g. one coordinator goroutine sweeping many caches from a single timer.
+func WithExternalSweep() Option {
+	return WithNoSweeper()
+}
+
+// WithMaxKeyLength makes Set reject keys longer than n bytes with ErrKeyTooLong. n <= 0 means no limit. It only
+// applies to caches whose key type is a string.
+func WithMaxKeyLength(n int) Option {
+	return func(o *options) {
+		o.maxKeyLen = n
+	}
+}
+
+// WithAdaptiveTTL scales down effective TTLs during sweeps under memory pressure. pressureFn reports pressure
+// from 0 (none) to 1 (critical); at pressure p an entry is reaped once (1-p) of its lifetime has passed.
+//
+// Experimental: every sweep under non-zero pressure scans all of ttlHK.
+func WithAdaptiveTTL(pressureFn func() float64) Option {
+	return func(o *options) {
+		o.pressureFn = pressureFn
+	}
+}
+
+// WithTopKTracking tracks the approximately k most accessed keys for TopKeys, using memory bounded by k rather
+// than by the number of keys.
+func WithTopKTracking(k int) Option {
+	return func(o *options) {
+		if k > 0 {
+			o.topKSize = k
+		}
+	}
+}
+
+// WithSkipEqualWrites makes Set on an existing key whose value is equal to the new one only refresh the
+// expiry: the value and version are kept and no change event fires. equal receives the cache's values as
+// interface{}.
+func WithSkipEqualWrites(equal func(a, b interface{}) bool) Option {
+	return func(o *options) {
+		o.equalFn = equal
+	}
+}
+
+// WithSelfHealing makes every sweep check that ttlHK is sorted and rebuild it if not, so an out-of-order index
+// can't hide expired entries from the sweep. The check costs a full pass over ttlHK per sweep.
+func WithSelfHealing() Option {
+	return func(o *options) {
+		o.selfHealing = true
+	}
+}
+
+// WithCleanupOnGet makes a Get that finds an expired entry remove it on the spot instead of leaving it for the
+// sweep. Such a Get briefly takes the write lock, so it contends with other readers.
+func WithCleanupOnGet() Option {
+	return func(o *options) {
+		o.cleanupOnGet = true
+	}
+}
+
+// WithEvictionPolicy chooses which entry Set evicts when adding a key to a full cache. The default is
+// EvictSoonestExpiry.
+func WithEvictionPolicy(p EvictionPolicy) Option {
+	return func(o *options) {
+		o.eviction = p
+	}
+}
+
+// WithMonotonicClock measures time from the cache's creation with the monotonic clock, so stepping the wall
+// clock (e.g. by NTP) neither extends nor shortens any entry's life. The trade-off is that expiries stop
+// tracking the wall clock: once it has been stepped, ExpiresAt and CreatedAt are off by the size of the step.
+func WithMonotonicClock() Option {
+	return func(o *options) {
+		o.monotonic = true
+	}
+}
+
+// WithEntryPooling reuses the entries of removed keys for new ones, cutting allocations when keys churn quickly.
+// A removed entry is only reused once the operation that removed it has released the lock.
+func WithEntryPooling() Option {
+	return func(o *options) {
+		o.entryPooling = true
+	}
+}
+
+// WithSlidingTTL makes every Get that hits push the entry's expiry out to the default TTL from now, so entries
+// live as long as they keep being read. An entry set with a longer TTL is never shortened, and a pinned entry
+// stays pinned. Get then takes the write lock, so reads no longer run concurrently.
+func WithSlidingTTL() Option {
+	return func(o *options) {
+		o.slidingTTL = true
+	}
+}
+
+// WithTracer reports every GetCtx hit or miss to t.
+func WithTracer(t Tracer) Option {
+	return func(o *options) {
+		o.tracer = t
+	}
+}
+
+// WithOnEvict calls fn with the key and value of every entry Set evicts to make room in a full cache. fn runs
+// after the cache's lock is released, so it may call back into the cache.
+func WithOnEvict(fn func(key, value interface{})) Option {
+	return func(o *options) {
+		o.onEvict = fn
+	}
+}
+
+// WithOnExpire calls fn with the key and value of every entry removed because its TTL passed, whether by the
+// sweep or by a Get WithCleanupOnGet. Like WithOnEvict, fn runs after the cache's lock is released.
+func WithOnExpire(fn func(key, value interface{})) Option {
+	return func(o *options) {
+		o.onExpire = fn
+	}
+}
+
+// WithExpiryJitter adds a random offset in [0, maxJitter) to the TTL of every write, including Touch and
+// WithSlidingTTL refreshes, so keys warmed together don't all expire in the same instant. Batch writes draw a
+// separate offset for each entry.
+func WithExpiryJitter(maxJitter time.Duration) Option {
+	return func(o *options) {
+		o.maxJitter = maxJitter
+	}
+}
+
+// WithMaxBytes limits the total size of the cached values to maxBytes, as measured by sizer, in addition to the
+// entry count. A write that would exceed it first evicts entries the eviction policy picks, soonest expiry
+// first by default, and a value larger than maxBytes on its own is rejected. sizer is called once per write
+// with the value being stored; Stats reports the current total as Bytes. maxBytes must be greater than 0 and
+// sizer must not be nil.
+func WithMaxBytes(maxBytes int64, sizer func(value interface{}) int) Option {
+	return func(o *options) {
+		o.maxBytes = maxBytes
+		o.sizer = sizer
+	}
+}
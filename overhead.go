@@ -0,0 +1,33 @@
+package ttl_cache
+
+import "unsafe"
+
+// Go maps fill buckets to an average load factor of 6.5 of 8 slots before growing.
+const mapLoadFactor = 6.5 / 8
+
+// OverheadBytes estimates the memory the cache itself uses on top of the stored values: the map's slots, the
+// entry structs and key bytes, and the full capacity of ttlHK. It is an approximation for budgeting, not an
+// exact accounting of the runtime's allocations.
+func (c *TTLCache[K, V]) OverheadBytes() int64 {
+	const ptrSize = int64(unsafe.Sizeof(uintptr(0)))
+	var (
+		keySize   = int64(unsafe.Sizeof(*new(K)))
+		entrySize = int64(unsafe.Sizeof(cacheEntry[K, V]{}))
+		// key, value pointer and one tophash byte per map slot
+		slotSize = keySize + ptrSize + 1
+	)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	overhead := int64(unsafe.Sizeof(*c))
+	overhead += int64(float64(int64(len(c.cache))*slotSize) / mapLoadFactor)
+	overhead += int64(cap(c.ttlHK)) * ptrSize
+	overhead += int64(len(c.cache)) * entrySize
+	for k := range c.cache {
+		if s, ok := keyString(k); ok {
+			overhead += int64(len(s))
+		}
+	}
+	return overhead
+}
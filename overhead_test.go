@@ -0,0 +1,36 @@
+package ttl_cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Empty cache reports the struct and ttlHK capacity
+// --Overhead grows with entry count
+// --Overhead grows with ttlHK capacity
+func TestCache_OverheadBytes(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	empty := cache.OverheadBytes()
+	assert.True(t, empty > 0)
+
+	require.Nil(t, cache.Set(key("one"), 1))
+	one := cache.OverheadBytes()
+	assert.True(t, one > empty)
+
+	for i := 0; i < 9; i++ {
+		require.Nil(t, cache.Set(key(fmt.Sprintf("key%d", i)), i))
+	}
+	full := cache.OverheadBytes()
+	assert.True(t, full > one)
+
+	cache.ttlHK = append(make([]*cacheEntry[key, interface{}], 0, 100), cache.ttlHK...)
+	assert.True(t, cache.OverheadBytes() > full)
+}
@@ -0,0 +1,86 @@
+package ttl_cache
+
+import (
+	"math"
+	"time"
+)
+
+// neverExpires is the exp of a pinned entry. It sorts pinned entries to the back of ttlHK, where the sweep's
+// early stop never reaches them.
+const neverExpires = math.MaxInt64
+
+// NoExpiry, given as a TTL to Set or any other write, or as the default TTL, stores an entry that never
+// expires, exactly as if it had been pinned with Pin.
+const NoExpiry time.Duration = -1
+
+// Pin makes key's live entry never expire until it is unpinned. A later Set on the key replaces the pin with
+// the new TTL.
+func (c *TTLCache[K, V]) Pin(key K) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.cache[key]
+	if !exists || entry.expired(c.getExp(0)) {
+		return newKeyNotFoundErr(key)
+	}
+
+	c.moveHKEntry(entry, neverExpires)
+	return nil
+}
+
+// Unpin makes a pinned entry expire again after ttl, or the default TTL if ttl is not positive. A ttl of
+// NoExpiry, or a default TTL of NoExpiry in its place, would leave the entry pinned, so it is rejected with an
+// invalid TTL error.
+func (c *TTLCache[K, V]) Unpin(key K, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.cache[key]
+	if !exists {
+		return newKeyNotFoundErr(key)
+	}
+	if !entry.pinned() {
+		return newNotPinnedErr(key)
+	}
+
+	if c.resolveTTL([]time.Duration{ttl}) == NoExpiry {
+		return newInvalidTTLErr(NoExpiry)
+	}
+	c.moveHKEntry(entry, c.writeExp([]time.Duration{ttl}))
+	return nil
+}
+
+func (e *cacheEntry[K, V]) pinned() bool {
+	return e.exp == neverExpires
+}
+
+// moveHKEntry changes entry's exp and moves it to its new sorted position in ttlHK. A SetChild child's exp is
+// capped at its parent's, and children are pulled in to an exp earlier than theirs.
+func (c *TTLCache[K, V]) moveHKEntry(entry *cacheEntry[K, V], exp int64) {
+	c.removeHKEntry(entry)
+	entry.exp = c.capToParent(entry, exp)
+	entry.writtenAt = c.getExp(0)
+	c.insertNewHKEntry(entry)
+	c.capChildren(entry)
+}
+
+// ExpireAllPinned makes every pinned entry expire after ttl, or the default TTL if ttl is not positive, and
+// returns how many entries it converted. If that TTL is NoExpiry the entries stay pinned and it returns 0.
+func (c *TTLCache[K, V]) ExpireAllPinned(ttl time.Duration) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	first := len(c.ttlHK)
+	for first > 0 && c.ttlHK[first-1].pinned() {
+		first--
+	}
+	pinned := append([]*cacheEntry[K, V](nil), c.ttlHK[first:]...)
+
+	if c.resolveTTL([]time.Duration{ttl}) == NoExpiry {
+		return 0
+	}
+	for _, entry := range pinned {
+		c.moveHKEntry(entry, c.writeExp([]time.Duration{ttl}))
+	}
+	return len(pinned)
+}
@@ -0,0 +1,134 @@
+package ttl_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// TestCases
+// -Success
+// --Pinned entry survives sweeps
+// --Unpinned entry expires normally
+// --ExpireAllPinned makes pinned entries sweepable again
+//
+// -Error
+// --Pin missing key
+// --Unpin entry that isn't pinned
+func TestCache_Pin(t *testing.T) {
+	pc := new(pinSuite)
+	suite.Run(t, pc)
+}
+
+type pinSuite struct {
+	cacheSuite
+}
+
+func (pc *pinSuite) SetupTest() {
+	pc.cacheSuite.SetupSuite()
+	require.Nil(pc.T(), pc.cache.Set(key("pinned"), "value", 5*time.Second))
+	require.Nil(pc.T(), pc.cache.Set(key("other"), "value", 10*time.Second))
+}
+
+func (pc *pinSuite) TestPin_SurvivesSweep() {
+	require.Nil(pc.T(), pc.cache.Pin(key("pinned")))
+	assert.Equal(pc.T(), key("pinned"), pc.cache.ttlHK[len(pc.cache.ttlHK)-1].key)
+	assert.True(pc.T(), pc.cache.cache[key("pinned")].info().ExpiresAt.IsZero())
+
+	pc.cache.evict(getExp(24 * time.Hour))
+
+	assertKeyMapsToValue(pc.T(), "value", key("pinned"), pc.cache)
+	assertKeyDoesNotExist(pc.T(), key("other"), pc.cache)
+	assert.Nil(pc.T(), pc.cache.checkInvariants())
+}
+
+func (pc *pinSuite) TestUnpin_RestoresExpiry() {
+	require.Nil(pc.T(), pc.cache.Pin(key("pinned")))
+	require.Nil(pc.T(), pc.cache.Unpin(key("pinned"), 20*time.Second))
+
+	assertExpNear(pc.T(), getExp(20*time.Second), pc.cache.cache[key("pinned")].exp)
+	assert.Nil(pc.T(), pc.cache.checkInvariants())
+
+	pc.cache.evict(getExp(30 * time.Second))
+	assertKeyDoesNotExist(pc.T(), key("pinned"), pc.cache)
+}
+
+func (pc *pinSuite) TestExpireAllPinned() {
+	require.Nil(pc.T(), pc.cache.Pin(key("pinned")))
+	require.Nil(pc.T(), pc.cache.Set(key("pinned2"), "value"))
+	require.Nil(pc.T(), pc.cache.Pin(key("pinned2")))
+
+	converted := pc.cache.ExpireAllPinned(20 * time.Second)
+	assert.Equal(pc.T(), 2, converted)
+	assertExpNear(pc.T(), getExp(20*time.Second), pc.cache.cache[key("pinned")].exp)
+	assertExpNear(pc.T(), getExp(20*time.Second), pc.cache.cache[key("pinned2")].exp)
+	assert.Nil(pc.T(), pc.cache.checkInvariants())
+	assert.Equal(pc.T(), 0, pc.cache.ExpireAllPinned(20*time.Second))
+
+	pc.cache.evict(getExp(30 * time.Second))
+	assertCacheHasNKeys(pc.T(), 0, pc.cache)
+}
+
+func (pc *pinSuite) TestPin_MissingKey() {
+	err := pc.cache.Pin(key("missing"))
+	assert.Equal(pc.T(), newKeyNotFoundErr(key("missing")), err)
+}
+
+func (pc *pinSuite) TestUnpin_NotPinned() {
+	err := pc.cache.Unpin(key("other"), time.Second)
+	assert.Equal(pc.T(), newNotPinnedErr(key("other")), err)
+	assertExpNear(pc.T(), getExp(10*time.Second), pc.cache.cache[key("other")].exp)
+}
+
+// TestCases
+// -Success
+// --NoExpiry entries survive several real sweep periods, sorted behind expiring entries
+// --NoExpiry as the default TTL applies to Sets without a TTL
+//
+// -Error
+// --Other negative default TTLs are still rejected
+// --Unpin and ExpireAllPinned leave entries pinned rather than unpin them to NoExpiry
+func TestCache_NoExpiry(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSweepPeriod(10 * time.Millisecond))
+	require.Nil(t, err)
+	defer cache.Close()
+	require.Nil(t, cache.Set(key("reference"), "value", NoExpiry))
+	require.Nil(t, cache.Set(key("short"), "value", 50*time.Millisecond))
+	assert.Equal(t, []key{"short", "reference"}, cache.Keys())
+
+	time.Sleep(150 * time.Millisecond)
+	value, err := cache.Get(key("reference"))
+	assert.Nil(t, err)
+	assert.Equal(t, "value", value)
+	//The short entry was swept even though a NoExpiry entry is still in ttlHK
+	cache.mu.RLock()
+	_, exists := cache.cache[key("short")]
+	cache.mu.RUnlock()
+	assert.False(t, exists)
+
+	forever, err := NewTTLCache[key, interface{}](WithDefaultTTL(NoExpiry))
+	require.Nil(t, err)
+	require.Nil(t, forever.Set(key("default"), "value"))
+	assert.True(t, forever.cache[key("default")].pinned())
+	require.Nil(t, forever.Set(key("explicit"), "value", time.Minute))
+	assertExpNear(t, getExp(time.Minute), forever.cache[key("explicit")].exp)
+
+	assert.Equal(t, newInvalidTTLErr(NoExpiry), cache.Unpin(key("reference"), NoExpiry))
+	assert.Equal(t, 0, cache.ExpireAllPinned(NoExpiry))
+	//Read through the lock; the sweeper is running
+	_, expiresAt, err := cache.GetWithExpiry(key("reference"))
+	assert.Nil(t, err)
+	assert.True(t, expiresAt.IsZero())
+	//With a NoExpiry default, a ttl that isn't positive falls back to NoExpiry too
+	assert.Equal(t, newInvalidTTLErr(NoExpiry), forever.Unpin(key("default"), 0))
+	assert.Equal(t, 0, forever.ExpireAllPinned(0))
+	assert.True(t, forever.cache[key("default")].pinned())
+	require.Nil(t, forever.Unpin(key("default"), time.Minute))
+	assertExpNear(t, getExp(time.Minute), forever.cache[key("default")].exp)
+
+	_, err = NewTTLCache[key, interface{}](WithDefaultTTL(-time.Second))
+	assert.Equal(t, newInvalidTTLErr(-time.Second), err)
+}
@@ -0,0 +1,47 @@
+package ttl_cache
+
+import "sync"
+
+// newEntry returns a cleared entry from the pool when the cache was created WithEntryPooling, or a new one.
+func (c *TTLCache[K, V]) newEntry(key K, value V, exp int64) *cacheEntry[K, V] {
+	if c.pool == nil {
+		return newCacheEntry(key, value, exp)
+	}
+	entry := c.pool.Get().(*cacheEntry[K, V])
+	*entry = cacheEntry[K, V]{
+		key:   key,
+		value: value,
+		exp:   exp,
+	}
+	return entry
+}
+
+// release marks an entry that has left the map and ttlHK for reuse, giving back its cost WithMaxBytes. The
+// caller may still be reading it, so it only goes back to the pool in releaseFreed, once the operation holding
+// the write lock is done.
+func (c *TTLCache[K, V]) release(entry *cacheEntry[K, V]) {
+	c.stats.addBytes(-entry.cost)
+	if c.pool == nil {
+		return
+	}
+	c.freed = append(c.freed, entry)
+}
+
+// releaseFreed clears every released entry and returns it to the pool. It must run under the write lock,
+// which also guarantees no Get is still reading a released entry.
+func (c *TTLCache[K, V]) releaseFreed() {
+	for i, entry := range c.freed {
+		*entry = cacheEntry[K, V]{}
+		c.pool.Put(entry)
+		c.freed[i] = nil
+	}
+	c.freed = c.freed[:0]
+}
+
+func newEntryPool[K comparable, V any]() *sync.Pool {
+	return &sync.Pool{
+		New: func() interface{} {
+			return new(cacheEntry[K, V])
+		},
+	}
+}
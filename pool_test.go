@@ -0,0 +1,64 @@
+package ttl_cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Removed entries are reused for new keys
+// --No live entry is ever handed out again while churning
+func TestCache_EntryPooling(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(4), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithEntryPooling())
+	require.Nil(t, err)
+
+	values := make(map[key]int)
+	for i := 0; i < 100; i++ {
+		k := key(fmt.Sprintf("key%d", i))
+		require.Nil(t, cache.Set(k, i, time.Duration(1+i%7)*time.Second))
+		values[k] = i
+		if i%3 == 0 {
+			require.Nil(t, cache.Delete(k))
+			delete(values, k)
+		}
+
+		require.Nil(t, cache.checkInvariants())
+		seen := make(map[*cacheEntry[key, interface{}]]key, len(cache.cache))
+		for k, entry := range cache.cache {
+			require.NotContains(t, seen, entry, "entry for %s reused for %s while live", seen[entry], k)
+			seen[entry] = k
+			assert.Equal(t, k, entry.key)
+			assert.Equal(t, values[k], entry.value)
+		}
+	}
+	assert.Empty(t, cache.freed)
+}
+
+func BenchmarkSet_Churn(b *testing.B) {
+	for _, pooling := range []bool{false, true} {
+		b.Run(fmt.Sprintf("pooling=%t", pooling), func(b *testing.B) {
+			opts := []Option{WithSize(128), WithDefaultTTL(30 * time.Second), WithSweepPeriod(time.Minute)}
+			if pooling {
+				opts = append(opts, WithEntryPooling())
+			}
+			cache, err := NewTTLCache[key, interface{}](opts...)
+			require.Nil(b, err)
+			defer cache.Close()
+			keys := make([]key, 1024)
+			for i := range keys {
+				keys[i] = key(fmt.Sprintf("key%d", i))
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = cache.Set(keys[i%len(keys)], i)
+			}
+		})
+	}
+}
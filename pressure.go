@@ -0,0 +1,38 @@
+package ttl_cache
+
+// evictUnderPressure reaps entries whose lifetime, scaled down by the current memory pressure, ended before
+// cutoff. Scaled expiries don't follow ttlHK order, so the whole index is scanned.
+func (c *TTLCache[K, V]) evictUnderPressure(cutoff int64) {
+	if c.pressureFn == nil {
+		return
+	}
+	pressure := c.pressureFn()
+	if pressure <= 0 {
+		return
+	}
+	if pressure > 1 {
+		pressure = 1
+	}
+
+	var reaped []*cacheEntry[K, V]
+	for _, entry := range c.ttlHK {
+		if entry.scaledExp(pressure) < cutoff {
+			reaped = append(reaped, entry)
+		}
+	}
+	for _, entry := range reaped {
+		if c.cache[entry.key] == entry {
+			c.removeEntry(entry, ChangeExpire)
+		}
+	}
+}
+
+// scaledExp is e's expiry with the TTL it was last given, by a write or a refresh such as Touch, scaled down by
+// pressure.
+func (e *cacheEntry[K, V]) scaledExp(pressure float64) int64 {
+	if e.pinned() || e.exp <= e.writtenAt {
+		return e.exp
+	}
+	lifetime := float64(e.exp - e.writtenAt)
+	return e.writtenAt + int64(lifetime*(1-pressure))
+}
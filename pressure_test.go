@@ -0,0 +1,90 @@
+package ttl_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --No pressure reaps nothing early
+// --Moderate pressure reaps the shortest lived entry
+// --High pressure reaps everything
+// --Pressure above 1 is clamped
+func TestCache_AdaptiveTTL(t *testing.T) {
+	type tc struct {
+		description       string
+		pressure          float64
+		expectedRemaining int
+	}
+
+	tcs := []tc{
+		{description: "no pressure", pressure: 0, expectedRemaining: 3},
+		{description: "moderate pressure", pressure: 0.5, expectedRemaining: 2},
+		{description: "high pressure", pressure: 0.9, expectedRemaining: 0},
+		{description: "pressure above 1", pressure: 2, expectedRemaining: 0},
+	}
+
+	for _, testCase := range tcs {
+		t.Run(testCase.description, func(t *testing.T) {
+			pressure := testCase.pressure
+			cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithAdaptiveTTL(func() float64 {
+				return pressure
+			}))
+			require.Nil(t, err)
+
+			require.Nil(t, cache.Set(key("10s"), "value", 10*time.Second))
+			require.Nil(t, cache.Set(key("20s"), "value", 20*time.Second))
+			require.Nil(t, cache.Set(key("40s"), "value", 40*time.Second))
+
+			cache.evict(getExp(6 * time.Second))
+			assertCacheHasNKeys(t, testCase.expectedRemaining, cache)
+			assert.Nil(t, cache.checkInvariants())
+		})
+	}
+}
+
+// TestCases
+// -Success
+// --An overwrite of an old key scales the TTL it was just given
+// --Touch and the sliding refresh restart the scaled lifetime too
+func TestCache_AdaptiveTTL_Rewrites(t *testing.T) {
+	now := time.Now()
+	newCache := func(opts ...Option) *TTLCache[key, interface{}] {
+		cache, err := NewTTLCache[key, interface{}](append([]Option{WithNoSweeper(), WithAdaptiveTTL(func() float64 {
+			return 0.5
+		})}, opts...)...)
+		require.Nil(t, err)
+		cache.now = func() time.Time {
+			return now
+		}
+		return cache
+	}
+
+	cache := newCache()
+	require.Nil(t, cache.Set(key("overwritten"), "value", 10*time.Second))
+	require.Nil(t, cache.Set(key("touched"), "value", 150*time.Second))
+	now = now.Add(100 * time.Second)
+	require.Nil(t, cache.Set(key("overwritten"), "value", 10*time.Second))
+	require.Nil(t, cache.Touch(key("touched"), 10*time.Second))
+
+	//Half of each fresh 10s lifetime is left
+	now = now.Add(4 * time.Second)
+	assert.Equal(t, 0, cache.TriggerSweep())
+	assert.True(t, cache.Has(key("overwritten")))
+	assert.True(t, cache.Has(key("touched")))
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, 2, cache.TriggerSweep())
+
+	sliding := newCache(WithSlidingTTL(), WithDefaultTTL(10*time.Second))
+	require.Nil(t, sliding.Set(key("read"), "value"))
+	now = now.Add(8 * time.Second)
+	_, err := sliding.Get(key("read"))
+	require.Nil(t, err)
+	now = now.Add(4 * time.Second)
+	assert.Equal(t, 0, sliding.TriggerSweep())
+	assert.True(t, sliding.Has(key("read")))
+}
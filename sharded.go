@@ -0,0 +1,109 @@
+package ttl_cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// FNV-1a parameters for 64-bit hashes, as in hash/fnv
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// ShardedTTLCache splits its keys across several TTLCaches, each with its own lock, index and sweep, so writes
+// to different shards don't serialize on one mutex. A key always maps to the same shard, by an FNV-1a hash of
+// its string form.
+type ShardedTTLCache[K comparable, V any] struct {
+	shards []*TTLCache[K, V]
+	mask   uint64
+}
+
+// NewShardedTTLCache creates a cache of shards shards, which must be a power of two. opts configure every
+// shard, except that the size given WithSize is the total across shards, split evenly and rounded up, and the
+// limit given WithMaxBytes is split evenly and rounded down, so the shards together never hold more. Each
+// shard evicts and sweeps on its own, so a full shard evicts even while others have room.
+func NewShardedTTLCache[K comparable, V any](shards int, opts ...Option) (*ShardedTTLCache[K, V], error) {
+	if shards <= 0 || shards&(shards-1) != 0 {
+		return nil, newInvalidShardCountErr(shards)
+	}
+
+	o := options{size: DefaultSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.size <= 0 {
+		return nil, newInvalidSizeErr(o.size)
+	}
+	perShard := (o.size + uint(shards) - 1) / uint(shards)
+	shardOpts := append(append([]Option(nil), opts...), WithSize(perShard))
+	if o.sizer != nil {
+		shardOpts = append(shardOpts, WithMaxBytes(o.maxBytes/int64(shards), o.sizer))
+	}
+
+	sc := &ShardedTTLCache[K, V]{
+		shards: make([]*TTLCache[K, V], shards),
+		mask:   uint64(shards - 1),
+	}
+	for i := range sc.shards {
+		shard, err := NewTTLCache[K, V](shardOpts...)
+		if err != nil {
+			_ = sc.Close()
+			return nil, err
+		}
+		sc.shards[i] = shard
+	}
+	return sc, nil
+}
+
+// Set stores value under key in key's shard, as TTLCache.Set.
+func (sc *ShardedTTLCache[K, V]) Set(key K, value V, optTTL ...time.Duration) error {
+	return sc.shard(key).Set(key, value, optTTL...)
+}
+
+// Get returns key's value from key's shard, as TTLCache.Get.
+func (sc *ShardedTTLCache[K, V]) Get(key K) (V, error) {
+	return sc.shard(key).Get(key)
+}
+
+// Delete removes key from key's shard, as TTLCache.Delete.
+func (sc *ShardedTTLCache[K, V]) Delete(key K) error {
+	return sc.shard(key).Delete(key)
+}
+
+// Len returns the number of live entries across all shards. Each shard is counted under its own lock, so the
+// total may mix moments when other goroutines are writing.
+func (sc *ShardedTTLCache[K, V]) Len() int {
+	n := 0
+	for _, shard := range sc.shards {
+		n += shard.Len()
+	}
+	return n
+}
+
+// Close stops every shard's sweep, as TTLCache.Close.
+func (sc *ShardedTTLCache[K, V]) Close() error {
+	for _, shard := range sc.shards {
+		if shard == nil {
+			continue
+		}
+		if err := shard.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *ShardedTTLCache[K, V]) shard(key K) *TTLCache[K, V] {
+	s, ok := keyString(key)
+	if !ok {
+		s = fmt.Sprint(key)
+	}
+	//FNV-1a, computed inline so routing a key doesn't allocate
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return sc.shards[h&sc.mask]
+}
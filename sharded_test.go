@@ -0,0 +1,104 @@
+package ttl_cache
+
+import (
+	"fmt"
+	"hash/fnv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Keys route to their FNV-1a shard and round-trip
+// --Size is split across shards
+// --The byte limit is split across shards, rounded down
+// --Delete and Len span shards
+//
+// -Error
+// --Shard count that isn't a power of two
+// --Zero size
+func TestShardedTTLCache(t *testing.T) {
+	cache, err := NewShardedTTLCache[key, interface{}](4, WithSize(10), WithDefaultTTL(30*time.Second))
+	require.Nil(t, err)
+	defer cache.Close()
+
+	for _, shard := range cache.shards {
+		assert.Equal(t, uint(3), shard.size)
+	}
+
+	for i := 0; i < 8; i++ {
+		k := key(fmt.Sprintf("key%d", i))
+		require.Nil(t, cache.Set(k, i))
+
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(k))
+		assertKeyMapsToValue(t, i, k, cache.shards[h.Sum64()&3])
+	}
+	assert.Equal(t, 8, cache.Len())
+
+	value, err := cache.Get(key("key3"))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, value)
+
+	require.Nil(t, cache.Delete(key("key3")))
+	_, err = cache.Get(key("key3"))
+	assert.Equal(t, newKeyNotFoundErr(key("key3")), err)
+	assert.Equal(t, 7, cache.Len())
+
+	for _, shards := range []int{0, 3, -4} {
+		_, err = NewShardedTTLCache[key, interface{}](shards)
+		assert.Equal(t, newInvalidShardCountErr(shards), err)
+	}
+	_, err = NewShardedTTLCache[key, interface{}](4, WithSize(0))
+	assert.Equal(t, newInvalidSizeErr(0), err)
+
+	limited, err := NewShardedTTLCache[key, interface{}](4, WithMaxBytes(10, func(interface{}) int { return 1 }))
+	require.Nil(t, err)
+	defer limited.Close()
+	for _, shard := range limited.shards {
+		assert.Equal(t, int64(2), shard.maxBytes)
+	}
+}
+
+func BenchmarkConcurrentSetGet(b *testing.B) {
+	keys := make([]key, 4096)
+	for i := range keys {
+		keys[i] = key(fmt.Sprintf("key%d", i))
+	}
+	run := func(b *testing.B, cache interface {
+		Set(key, interface{}, ...time.Duration) error
+		Get(key) (interface{}, error)
+	}) {
+		b.SetParallelism(16)
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				k := keys[i%len(keys)]
+				if i%4 == 0 {
+					_ = cache.Set(k, i)
+				} else {
+					_, _ = cache.Get(k)
+				}
+				i++
+			}
+		})
+	}
+
+	b.Run("single lock", func(b *testing.B) {
+		cache, err := NewTTLCache[key, interface{}](WithSize(8192))
+		require.Nil(b, err)
+		defer cache.Close()
+		run(b, cache)
+	})
+
+	b.Run("16 shards", func(b *testing.B) {
+		cache, err := NewShardedTTLCache[key, interface{}](16, WithSize(8192))
+		require.Nil(b, err)
+		defer cache.Close()
+		run(b, cache)
+	})
+}
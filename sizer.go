@@ -0,0 +1,36 @@
+package ttl_cache
+
+// cost returns value's size according to the WithMaxBytes sizer, or 0 without one.
+func (c *TTLCache[K, V]) cost(value V) int64 {
+	if c.sizer == nil {
+		return 0
+	}
+	return int64(c.sizer(value))
+}
+
+// reserveBytes evicts entries chosen by the eviction policy until a value costing cost fits under the
+// WithMaxBytes limit in place of held bytes already charged for key. Neither the keep entries nor their
+// SetChild parents are evicted, since removing a parent would remove its children with it.
+func (c *TTLCache[K, V]) reserveBytes(key K, cost, held int64, keep ...*cacheEntry[K, V]) error {
+	if c.sizer == nil {
+		return nil
+	}
+	if cost > c.maxBytes {
+		return newEntryTooLargeErr(key, cost, c.maxBytes)
+	}
+
+	for c.stats.bytesUsed()+cost-held > c.maxBytes {
+		victim := c.victim(keep...)
+		if victim == nil {
+			return newCacheFullErr(key)
+		}
+		c.removeEntry(victim, ChangeEvict)
+	}
+	return nil
+}
+
+// charge records that entry now holds a value costing cost.
+func (c *TTLCache[K, V]) charge(entry *cacheEntry[K, V], cost int64) {
+	c.stats.addBytes(cost - entry.cost)
+	entry.cost = cost
+}
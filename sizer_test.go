@@ -0,0 +1,97 @@
+package ttl_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --New keys evict on the byte limit while well under the entry limit
+// --Growing an overwrite evicts other entries, never the one being written
+// --Deletes, sweeps and Clear give their bytes back
+//
+// -Error
+// --Value larger than the limit is rejected
+// --A nil sizer or a limit that isn't positive is rejected at construction
+func TestCache_MaxBytes(t *testing.T) {
+	stringLen := func(value interface{}) int {
+		return len(value.(string))
+	}
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithMaxBytes(10, stringLen))
+	require.Nil(t, err)
+
+	require.Nil(t, cache.Set(key("a"), "aaaa", time.Minute))
+	require.Nil(t, cache.Set(key("b"), "bbbb", 2*time.Minute))
+	require.Nil(t, cache.Set(key("c"), "cccc", 3*time.Minute))
+	assertKeyDoesNotExist(t, key("a"), cache)
+	assertCacheHasNKeys(t, 2, cache)
+	assert.Equal(t, int64(8), cache.Stats().Bytes)
+
+	require.Nil(t, cache.Set(key("b"), "bbbbbb", 2*time.Minute))
+	assert.Equal(t, int64(10), cache.Stats().Bytes)
+	//b expires soonest, so it goes to make room for c to grow
+	require.Nil(t, cache.Set(key("c"), "cccccccc", time.Minute))
+	assertKeyDoesNotExist(t, key("b"), cache)
+	assertKeyMapsToValue(t, "cccccccc", key("c"), cache)
+	assert.Equal(t, int64(8), cache.Stats().Bytes)
+	assert.Equal(t, uint64(2), cache.Stats().Evictions)
+
+	err = cache.Set(key("huge"), "hhhhhhhhhhh")
+	assert.Equal(t, newEntryTooLargeErr(key("huge"), 11, 10), err)
+	assertKeyDoesNotExist(t, key("huge"), cache)
+	assert.Equal(t, int64(8), cache.Stats().Bytes)
+
+	require.Nil(t, cache.Set(key("d"), "dd"))
+	require.Nil(t, cache.Delete(key("d")))
+	assert.Equal(t, int64(8), cache.Stats().Bytes)
+	cache.moveHKEntry(cache.cache[key("c")], getExp(-time.Second))
+	cache.SweepTick()
+	assert.Equal(t, int64(0), cache.Stats().Bytes)
+
+	require.Nil(t, cache.Set(key("e"), "eeeee"))
+	cache.Clear()
+	assert.Equal(t, int64(0), cache.Stats().Bytes)
+	assert.Nil(t, cache.checkInvariants())
+
+	_, err = NewTTLCache[key, interface{}](WithMaxBytes(10, nil))
+	assert.Equal(t, newNilSizerErr(), err)
+	for _, maxBytes := range []int64{0, -1} {
+		_, err = NewTTLCache[key, interface{}](WithMaxBytes(maxBytes, stringLen))
+		assert.Equal(t, newInvalidMaxBytesErr(maxBytes), err)
+	}
+}
+
+// TestCases
+// -Success
+// --Growing a child evicts other entries, never its parent
+//
+// -Error
+// --Growing a child that only its parent could make room for fails and leaves both in place
+func TestCache_MaxBytes_KeepsParent(t *testing.T) {
+	stringLen := func(value interface{}) int {
+		return len(value.(string))
+	}
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithMaxBytes(10, stringLen))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("p"), "pp", time.Minute))
+	require.Nil(t, cache.SetChild(key("p"), key("c"), "cc"))
+	require.Nil(t, cache.Set(key("x"), "xx", time.Hour))
+
+	//p expires soonest, but evicting it would take c with it
+	require.Nil(t, cache.Update(key("c"), "cccccccc"))
+	assertKeyDoesNotExist(t, key("x"), cache)
+	assertKeyMapsToValue(t, "pp", key("p"), cache)
+	assertKeyMapsToValue(t, "cccccccc", key("c"), cache)
+	assert.Equal(t, int64(10), cache.Stats().Bytes)
+
+	assert.Equal(t, newCacheFullErr(key("c")), cache.Update(key("c"), "ccccccccc"))
+	assert.Equal(t, newCacheFullErr(key("c")), cache.Set(key("c"), "ccccccccc"))
+	assertKeyMapsToValue(t, "pp", key("p"), cache)
+	assertKeyMapsToValue(t, "cccccccc", key("c"), cache)
+	assert.Equal(t, int64(10), cache.Stats().Bytes)
+	assert.Nil(t, cache.checkInvariants())
+}
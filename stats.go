@@ -0,0 +1,92 @@
+package ttl_cache
+
+import "sync/atomic"
+
+// Stats counts cache activity since the cache was created or ResetStats was last called.
+type Stats struct {
+	//Hits and Misses count lookups by Get, GetMany, GetWithExpiry, GetCtx and GetOrSet
+	Hits   uint64
+	Misses uint64
+	//Evictions counts entries removed to make room in a full cache
+	Evictions uint64
+	//Expirations counts entries removed because their TTL passed
+	Expirations uint64
+	//Sets counts successful writes, including overwrites
+	Sets uint64
+	//Bytes is the total size of the cached values WithMaxBytes. It is current usage, so ResetStats keeps it
+	Bytes int64
+}
+
+// cacheStats holds the counters behind Stats. They are updated atomically because Get only holds the read
+// lock.
+type cacheStats struct {
+	hits        uint64
+	misses      uint64
+	evictions   uint64
+	expirations uint64
+	sets        uint64
+	bytes       int64
+}
+
+// Stats returns the cache's counters. The counters are read one at a time, so a snapshot taken while other
+// goroutines use the cache may mix values from slightly different moments.
+func (c *TTLCache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:        atomic.LoadUint64(&c.stats.hits),
+		Misses:      atomic.LoadUint64(&c.stats.misses),
+		Evictions:   atomic.LoadUint64(&c.stats.evictions),
+		Expirations: atomic.LoadUint64(&c.stats.expirations),
+		Sets:        atomic.LoadUint64(&c.stats.sets),
+		Bytes:       c.stats.bytesUsed(),
+	}
+}
+
+// ResetStats sets every counter back to zero. Bytes measures what the cache holds rather than activity, so it
+// is left alone.
+func (c *TTLCache[K, V]) ResetStats() {
+	atomic.StoreUint64(&c.stats.hits, 0)
+	atomic.StoreUint64(&c.stats.misses, 0)
+	atomic.StoreUint64(&c.stats.evictions, 0)
+	atomic.StoreUint64(&c.stats.expirations, 0)
+	atomic.StoreUint64(&c.stats.sets, 0)
+}
+
+func (s *cacheStats) recordGet(err error) {
+	switch {
+	case err == nil:
+		s.recordHit()
+	case err != ErrCacheClosed:
+		s.recordMiss()
+	}
+}
+
+func (s *cacheStats) recordHit() {
+	atomic.AddUint64(&s.hits, 1)
+}
+
+func (s *cacheStats) recordMiss() {
+	atomic.AddUint64(&s.misses, 1)
+}
+
+func (s *cacheStats) recordSet() {
+	atomic.AddUint64(&s.sets, 1)
+}
+
+func (s *cacheStats) recordRemoval(changeType ChangeType) {
+	switch changeType {
+	case ChangeEvict:
+		atomic.AddUint64(&s.evictions, 1)
+	case ChangeExpire:
+		atomic.AddUint64(&s.expirations, 1)
+	}
+}
+
+func (s *cacheStats) addBytes(delta int64) {
+	if delta != 0 {
+		atomic.AddInt64(&s.bytes, delta)
+	}
+}
+
+func (s *cacheStats) bytesUsed() int64 {
+	return atomic.LoadInt64(&s.bytes)
+}
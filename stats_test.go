@@ -0,0 +1,60 @@
+package ttl_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Gets, sets, evictions and expirations are counted exactly
+// --Reads other than Get are not counted
+// --Every writer counts its writes as sets
+// --ResetStats zeroes every counter
+func TestCache_Stats(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(2), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	require.Nil(t, cache.Set(key("a"), "a", 10*time.Second))
+	require.Nil(t, cache.Set(key("b"), "b"))
+	require.Nil(t, cache.Set(key("b"), "b2"))
+	//Evicts a, the entry expiring soonest
+	require.Nil(t, cache.Set(key("c"), "c"))
+
+	_, err = cache.Get(key("b"))
+	require.Nil(t, err)
+	_, err = cache.Get(key("c"))
+	require.Nil(t, err)
+	_, err = cache.Get(key("a"))
+	require.NotNil(t, err)
+	_, _ = cache.Peek(key("b"))
+
+	cache.moveHKEntry(cache.cache[key("c")], getExp(-time.Second))
+	cache.SweepTick()
+
+	assert.Equal(t, Stats{
+		Hits:        2,
+		Misses:      1,
+		Evictions:   1,
+		Expirations: 1,
+		Sets:        4,
+	}, cache.Stats())
+
+	cache.ResetStats()
+	assert.Equal(t, Stats{}, cache.Stats())
+
+	require.Nil(t, cache.Set(key("c"), "c"))
+	require.Nil(t, cache.Update(key("b"), "b3"))
+	require.Nil(t, cache.Migrate(key("b"), func(old interface{}) (interface{}, bool, error) {
+		return "b4", true, nil
+	}))
+	//An unchanged migration writes nothing
+	require.Nil(t, cache.Migrate(key("b"), func(old interface{}) (interface{}, bool, error) {
+		return old, false, nil
+	}))
+	require.Nil(t, cache.SwapKeys(key("b"), key("c")))
+	assert.Equal(t, uint64(5), cache.Stats().Sets)
+}
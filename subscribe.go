@@ -0,0 +1,131 @@
+package ttl_cache
+
+import "sync"
+
+type ChangeType int
+
+const (
+	ChangeSet ChangeType = iota
+	ChangeDelete
+	ChangeExpire
+	ChangeEvict
+)
+
+func (ct ChangeType) String() string {
+	switch ct {
+	case ChangeSet:
+		return "set"
+	case ChangeDelete:
+		return "delete"
+	case ChangeExpire:
+		return "expire"
+	case ChangeEvict:
+		return "evict"
+	}
+	return "unknown"
+}
+
+type ChangeEvent[V any] struct {
+	Type  ChangeType
+	Value V
+}
+
+type subscriber[V any] struct {
+	ch   chan ChangeEvent[V]
+	once sync.Once
+}
+
+type pendingEvent[K comparable, V any] struct {
+	key   K
+	event ChangeEvent[V]
+}
+
+// Subscribe returns a channel receiving every change to key, plus a function that ends the subscription and
+// closes the channel. Delivery never blocks the cache: if the channel's buffer is full the event is dropped.
+func (c *TTLCache[K, V]) Subscribe(key K, buffer int) (<-chan ChangeEvent[V], func()) {
+	if buffer < 0 {
+		buffer = 0
+	}
+	sub := &subscriber[V]{ch: make(chan ChangeEvent[V], buffer)}
+
+	c.subsMu.Lock()
+	c.subs[key] = append(c.subs[key], sub)
+	c.subsMu.Unlock()
+
+	return sub.ch, func() {
+		sub.once.Do(func() {
+			c.unsubscribe(key, sub)
+		})
+	}
+}
+
+func (c *TTLCache[K, V]) unsubscribe(key K, sub *subscriber[V]) {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	subs := c.subs[key]
+	for i, s := range subs {
+		if s == sub {
+			subs = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(subs) == 0 {
+		delete(c.subs, key)
+	} else {
+		c.subs[key] = subs
+	}
+	close(sub.ch)
+}
+
+// queueEvent records a change to be delivered once the caller releases c.mu. Every removal passes through
+// here, so it also counts evictions and expirations for Stats.
+func (c *TTLCache[K, V]) queueEvent(key K, changeType ChangeType, value V) {
+	c.stats.recordRemoval(changeType)
+
+	c.subsMu.Lock()
+	_, subscribed := c.subs[key]
+	c.subsMu.Unlock()
+	if !subscribed && c.callback(changeType) == nil {
+		return
+	}
+
+	c.pendingEvents = append(c.pendingEvents, pendingEvent[K, V]{
+		key:   key,
+		event: ChangeEvent[V]{Type: changeType, Value: value},
+	})
+}
+
+func (c *TTLCache[K, V]) deliverEvents(events []pendingEvent[K, V]) {
+	if len(events) == 0 {
+		return
+	}
+
+	for _, pe := range events {
+		if fn := c.callback(pe.event.Type); fn != nil {
+			fn(pe.key, pe.event.Value)
+		}
+	}
+
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	for _, pe := range events {
+		for _, sub := range c.subs[pe.key] {
+			select {
+			case sub.ch <- pe.event:
+			default:
+			}
+		}
+	}
+}
+
+// callback returns the function set WithOnEvict or WithOnExpire for changeType, or nil.
+func (c *TTLCache[K, V]) callback(changeType ChangeType) func(key, value interface{}) {
+	switch changeType {
+	case ChangeEvict:
+		return c.onEvict
+	case ChangeExpire:
+		return c.onExpire
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package ttl_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// TestCases
+// -Success
+// --Set, overwrite and expire are delivered in order
+// --Changes to other keys are not delivered
+// --Unsubscribe closes the channel and stops delivery
+// --Full buffer drops events instead of blocking
+func TestCache_Subscribe(t *testing.T) {
+	sc := new(subscribeSuite)
+	suite.Run(t, sc)
+}
+
+type subscribeSuite struct {
+	cacheSuite
+}
+
+func (sc *subscribeSuite) SetupTest() {
+	sc.cacheSuite.SetupSuite()
+}
+
+func (sc *subscribeSuite) TestSubscribe_ReceivesEvents() {
+	k := key("watched")
+	events, unsubscribe := sc.cache.Subscribe(k, 10)
+	defer unsubscribe()
+
+	require.Nil(sc.T(), sc.cache.Set(k, "first"))
+	require.Nil(sc.T(), sc.cache.Set(k, "second"))
+
+	//Force the entry to expire
+	sc.cache.moveHKEntry(sc.cache.cache[k], time.Now().Add(-5*time.Second).UnixNano())
+	sc.cache.SweepTick()
+
+	assert.Equal(sc.T(), ChangeEvent[interface{}]{Type: ChangeSet, Value: "first"}, <-events)
+	assert.Equal(sc.T(), ChangeEvent[interface{}]{Type: ChangeSet, Value: "second"}, <-events)
+	assert.Equal(sc.T(), ChangeEvent[interface{}]{Type: ChangeExpire, Value: "second"}, <-events)
+	assertNoEvent(sc.T(), events)
+}
+
+func (sc *subscribeSuite) TestSubscribe_OtherKeysIgnored() {
+	events, unsubscribe := sc.cache.Subscribe(key("watched"), 10)
+	defer unsubscribe()
+
+	require.Nil(sc.T(), sc.cache.Set(key("other"), "value"))
+	assertNoEvent(sc.T(), events)
+}
+
+func (sc *subscribeSuite) TestSubscribe_Unsubscribe() {
+	k := key("watched")
+	events, unsubscribe := sc.cache.Subscribe(k, 10)
+
+	require.Nil(sc.T(), sc.cache.Set(k, "before"))
+	assert.Equal(sc.T(), ChangeEvent[interface{}]{Type: ChangeSet, Value: "before"}, <-events)
+
+	unsubscribe()
+	assert.NotPanics(sc.T(), unsubscribe)
+
+	require.Nil(sc.T(), sc.cache.Set(k, "after"))
+	_, open := <-events
+	assert.False(sc.T(), open)
+	assert.Empty(sc.T(), sc.cache.subs)
+}
+
+func (sc *subscribeSuite) TestSubscribe_FullBufferDrops() {
+	k := key("watched")
+	events, unsubscribe := sc.cache.Subscribe(k, 1)
+	defer unsubscribe()
+
+	require.Nil(sc.T(), sc.cache.Set(k, "kept"))
+	require.Nil(sc.T(), sc.cache.Set(k, "dropped"))
+
+	assert.Equal(sc.T(), ChangeEvent[interface{}]{Type: ChangeSet, Value: "kept"}, <-events)
+	assertNoEvent(sc.T(), events)
+}
+
+// TestCases
+// -Success
+// --Capacity eviction calls OnEvict once and not OnExpire
+// --Sweep expiry calls OnExpire once and not OnEvict
+// --Callbacks may call back into the cache
+func TestCache_Callbacks(t *testing.T) {
+	type removal struct {
+		key   interface{}
+		value interface{}
+	}
+	var evicted, expired []removal
+	var cache *TTLCache[key, interface{}]
+	cache, err := NewTTLCache[key, interface{}](WithSize(2), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second),
+		WithOnEvict(func(k, value interface{}) {
+			evicted = append(evicted, removal{k, value})
+			//A callback runs unlocked, so it can use the cache
+			_, _ = cache.Peek(k.(key))
+		}),
+		WithOnExpire(func(k, value interface{}) {
+			expired = append(expired, removal{k, value})
+			_ = cache.Set(key("refilled"), value)
+		}),
+	)
+	require.Nil(t, err)
+
+	require.Nil(t, cache.Set(key("soonest"), "soonest", 10*time.Second))
+	require.Nil(t, cache.Set(key("later"), "later"))
+	require.Nil(t, cache.Set(key("new"), "new"))
+	assert.Equal(t, []removal{{key("soonest"), "soonest"}}, evicted)
+	assert.Empty(t, expired)
+
+	cache.moveHKEntry(cache.cache[key("later")], getExp(-time.Second))
+	cache.SweepTick()
+	assert.Equal(t, []removal{{key("later"), "later"}}, expired)
+	assert.Len(t, evicted, 1)
+	assertKeyMapsToValue(t, "later", key("refilled"), cache)
+}
+
+func assertNoEvent(t *testing.T, events <-chan ChangeEvent[interface{}]) {
+	select {
+	case ev := <-events:
+		assert.Fail(t, "unexpected event", "%+v", ev)
+	default:
+	}
+}
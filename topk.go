@@ -0,0 +1,76 @@
+package ttl_cache
+
+import (
+	"sort"
+	"sync"
+)
+
+// KeyCount is an approximate access count. Count may overestimate by up to the count of the key it displaced
+// from the summary.
+type KeyCount[K comparable] struct {
+	Key   K
+	Count uint64
+}
+
+// topK is a space-saving summary: it tracks at most k keys, and a new key replaces the least counted one,
+// inheriting its count. Keys accessed more than 1/k of the time are guaranteed to be tracked. It has its own
+// lock so that Get can record accesses while holding only the cache's read lock.
+type topK[K comparable] struct {
+	mu     sync.Mutex
+	k      int
+	counts map[K]uint64
+}
+
+func newTopK[K comparable](k int) *topK[K] {
+	return &topK[K]{
+		k:      k,
+		counts: make(map[K]uint64, k),
+	}
+}
+
+func (t *topK[K]) record(k K) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, tracked := t.counts[k]; tracked || len(t.counts) < t.k {
+		t.counts[k]++
+		return
+	}
+
+	var minKey K
+	var minCount uint64
+	first := true
+	for tk, count := range t.counts {
+		if first || count < minCount {
+			minKey, minCount, first = tk, count, false
+		}
+	}
+	delete(t.counts, minKey)
+	t.counts[k] = minCount + 1
+}
+
+func (t *topK[K]) top() []KeyCount[K] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	top := make([]KeyCount[K], 0, len(t.counts))
+	for k, count := range t.counts {
+		top = append(top, KeyCount[K]{Key: k, Count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return keyLess(top[i].Key, top[j].Key)
+	})
+	return top
+}
+
+// TopKeys returns the most accessed keys by Get, highest count first. It is empty unless the cache was built
+// with WithTopKTracking.
+func (c *TTLCache[K, V]) TopKeys() []KeyCount[K] {
+	if c.topK == nil {
+		return nil
+	}
+	return c.topK.top()
+}
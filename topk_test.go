@@ -0,0 +1,46 @@
+package ttl_cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCases
+// -Success
+// --Skewed access reports the heavy hitters first
+// --Tracking disabled reports nothing
+func TestCache_TopKeys(t *testing.T) {
+	t.Run("skewed access", func(t *testing.T) {
+		cache, err := NewTTLCache[key, interface{}](WithSize(50), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithTopKTracking(3))
+		require.Nil(t, err)
+
+		access := func(k key, n int) {
+			for i := 0; i < n; i++ {
+				_, _ = cache.Get(k)
+			}
+		}
+		for i := 0; i < 20; i++ {
+			access(key(fmt.Sprintf("cold%d", i)), 2)
+			access(key("hot"), 10)
+			access(key("warm"), 5)
+		}
+
+		top := cache.TopKeys()
+		require.Len(t, top, 3)
+		assert.Equal(t, key("hot"), top[0].Key)
+		assert.Equal(t, key("warm"), top[1].Key)
+		assert.True(t, top[0].Count >= 200)
+		assert.True(t, top[1].Count >= 100)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+		require.Nil(t, err)
+		_, _ = cache.Get(key("key"))
+		assert.Empty(t, cache.TopKeys())
+	})
+}
@@ -0,0 +1,42 @@
+package ttl_cache
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer records cache accesses, typically as attributes on the span carried by ctx.
+type Tracer interface {
+	TraceGet(ctx context.Context, key interface{}, hit bool)
+}
+
+// GetCtx is Get, additionally reporting the hit or miss to the configured Tracer. It returns ctx.Err() without
+// looking key up if ctx is already done.
+func (c *TTLCache[K, V]) GetCtx(ctx context.Context, key K) (V, error) {
+	if err := ctx.Err(); err != nil {
+		var zero V
+		return zero, err
+	}
+	value, err := c.Get(key)
+	if c.tracer != nil {
+		c.tracer.TraceGet(ctx, key, err == nil)
+	}
+	return value, err
+}
+
+// SetCtx is Set, except that it returns ctx.Err() and stores nothing if ctx is done by the time it holds the
+// lock.
+func (c *TTLCache[K, V]) SetCtx(ctx context.Context, key K, value V, optTTL ...time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	_, err := c.set(key, value, c.writeExp(optTTL))
+	return err
+}
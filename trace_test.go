@@ -0,0 +1,102 @@
+package ttl_cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spanKey struct{}
+
+type tracedGet struct {
+	span string
+	key  interface{}
+	hit  bool
+}
+
+type mockTracer struct {
+	gets []tracedGet
+}
+
+func (mt *mockTracer) TraceGet(ctx context.Context, key interface{}, hit bool) {
+	span, _ := ctx.Value(spanKey{}).(string)
+	mt.gets = append(mt.gets, tracedGet{span: span, key: key, hit: hit})
+}
+
+// TestCases
+// -Success
+// --Hit and miss are recorded on the caller's span
+// --No tracer behaves like Get
+//
+// -Error
+// --Cancelled context returns ctx.Err() without a lookup
+func TestCache_GetCtx(t *testing.T) {
+	t.Run("with tracer", func(t *testing.T) {
+		tracer := &mockTracer{}
+		cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithTracer(tracer))
+		require.Nil(t, err)
+		require.Nil(t, cache.Set(key("present"), "value"))
+		ctx := context.WithValue(context.Background(), spanKey{}, "request-span")
+
+		value, err := cache.GetCtx(ctx, key("present"))
+		assert.Nil(t, err)
+		assert.Equal(t, "value", value)
+
+		value, err = cache.GetCtx(ctx, key("absent"))
+		assert.Equal(t, newKeyNotFoundErr(key("absent")), err)
+		assert.Nil(t, value)
+
+		assert.Equal(t, []tracedGet{
+			{span: "request-span", key: key("present"), hit: true},
+			{span: "request-span", key: key("absent"), hit: false},
+		}, tracer.gets)
+	})
+
+	t.Run("without tracer", func(t *testing.T) {
+		cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+		require.Nil(t, err)
+		require.Nil(t, cache.Set(key("present"), "value"))
+
+		value, err := cache.GetCtx(context.Background(), key("present"))
+		assert.Nil(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		tracer := &mockTracer{}
+		cache, err := NewTTLCache[key, interface{}](WithTracer(tracer))
+		require.Nil(t, err)
+		require.Nil(t, cache.Set(key("present"), "value"))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		value, err := cache.GetCtx(ctx, key("present"))
+		assert.Equal(t, context.Canceled, err)
+		assert.Nil(t, value)
+		assert.Empty(t, tracer.gets)
+		assert.Equal(t, Stats{Sets: 1}, cache.Stats())
+	})
+}
+
+// TestCases
+// -Success
+// --Live context stores the value
+//
+// -Error
+// --Cancelled context returns ctx.Err() and stores nothing
+func TestCache_SetCtx(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithDefaultTTL(30 * time.Second))
+	require.Nil(t, err)
+
+	require.Nil(t, cache.SetCtx(context.Background(), key("stored"), "value", time.Minute))
+	assertKeyMapsToValue(t, "value", key("stored"), cache)
+	assertExpNear(t, getExp(time.Minute), cache.cache[key("stored")].exp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, cache.SetCtx(ctx, key("cancelled"), "value"))
+	assertKeyDoesNotExist(t, key("cancelled"), cache)
+}
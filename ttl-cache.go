@@ -1,91 +1,727 @@
 package ttl_cache
 
 import (
+	"math"
+	"math/rand"
 	"sort"
+	"sync"
 	"time"
 )
 
-type key string
-type cacheEntry struct {
-	value interface{}
-	key   key
-	exp   uint32
+type cacheEntry[K comparable, V any] struct {
+	//lastAccess is first so atomic access is 64-bit aligned on 32-bit platforms
+	lastAccess int64
+	value      V
+	key        K
+	exp        int64
+	createdAt  int64
+	version    uint64
+	parent     K
+	hasParent  bool
+	//cost is the value's size WithMaxBytes
+	cost int64
+	//missing marks a SetMissing tombstone
+	missing bool
+	//writtenAt is when exp was last set; WithAdaptiveTTL scales the lifetime from there
+	writtenAt int64
 }
-type TTLCache struct {
-	defaultTTL  time.Duration
-	cache       map[key]*cacheEntry
+
+// EntryInfo describes an entry's metadata. ExpiresAt is zero for a pinned entry. CreatedAt is when the key was
+// first stored; Version starts at 1 and increments on every overwrite.
+type EntryInfo struct {
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	Version   uint64
+}
+
+// TTLCache maps keys of type K to values of type V, each expiring after its TTL.
+type TTLCache[K comparable, V any] struct {
+	options
+
+	cache       map[K]*cacheEntry[K, V]
 	sweepTicker *time.Ticker
-	ttlHK       []*cacheEntry
-	size uint
+	ttlHK       []*cacheEntry[K, V]
+	mu          sync.RWMutex
+	done        chan struct{}
+	closeOnce   sync.Once
+	closed      bool
+	topK        *topK[K]
+	now         func() time.Time
+	since       func(time.Time) time.Duration
+	monoBase    time.Time
+	pool        *sync.Pool
+	freed       []*cacheEntry[K, V]
+	stats       *cacheStats
+
+	children map[K]map[K]struct{}
+
+	flightsMu sync.Mutex
+	flights   map[K]*flight[V]
+
+	subsMu        sync.Mutex
+	subs          map[K][]*subscriber[V]
+	pendingEvents []pendingEvent[K, V]
 }
 
-func NewTTLCache(numSize uint, defaultTTL, sweepPeriod time.Duration) (*TTLCache, error) {
-	if numSize <= 0 {
-		return nil, newInvalidSizeErr(numSize)
+// NewTTLCache creates a cache configured by opts. Without WithSize, WithDefaultTTL or WithSweepPeriod it holds
+// up to DefaultSize entries, which expire after DefaultTTL unless Set is given another TTL, with expired entries
+// swept every DefaultSweepPeriod. The key and value types are given explicitly, e.g.
+// NewTTLCache[string, *Session](WithSize(100)).
+func NewTTLCache[K comparable, V any](opts ...Option) (*TTLCache[K, V], error) {
+	c := &TTLCache[K, V]{
+		options: options{
+			size:        DefaultSize,
+			defaultTTL:  DefaultTTL,
+			sweepPeriod: DefaultSweepPeriod,
+		},
+		done:     make(chan struct{}),
+		children: make(map[K]map[K]struct{}),
+		subs:     make(map[K][]*subscriber[V]),
+		flights:  make(map[K]*flight[V]),
+		stats:    new(cacheStats),
+		now:      time.Now,
+		since:    time.Since,
+	}
+	for _, opt := range opts {
+		opt(&c.options)
+	}
+
+	if c.size <= 0 {
+		return nil, newInvalidSizeErr(c.size)
 	}
 
-	if defaultTTL <= 0*time.Second {
-		return nil, newInvalidTTLErr(defaultTTL)
+	if c.defaultTTL <= 0*time.Second && c.defaultTTL != NoExpiry {
+		return nil, newInvalidTTLErr(c.defaultTTL)
 	}
 
-	if sweepPeriod <= 0*time.Second {
-		return nil, newInvalidSweepPeriodErr(sweepPeriod)
+	if !c.noSweeper && c.sweepPeriod <= 0*time.Second {
+		return nil, newInvalidSweepPeriodErr(c.sweepPeriod)
 	}
 
-	return &TTLCache{
-		defaultTTL:  defaultTTL,
-		cache:       make(map[key]*cacheEntry, numSize),
-		sweepTicker: time.NewTicker(sweepPeriod),
-		ttlHK:       make([]*cacheEntry, 0, numSize),
-		size: numSize,
-	}, nil
+	if c.sizer == nil && c.maxBytes != 0 {
+		return nil, newNilSizerErr()
+	}
+	if c.sizer != nil && c.maxBytes <= 0 {
+		return nil, newInvalidMaxBytesErr(c.maxBytes)
+	}
+
+	c.cache = make(map[K]*cacheEntry[K, V], c.size)
+	c.ttlHK = make([]*cacheEntry[K, V], 0, c.size)
+	if c.topKSize > 0 {
+		c.topK = newTopK[K](c.topKSize)
+	}
+	if c.monotonic {
+		c.monoBase = c.now()
+	}
+	if c.entryPooling {
+		c.pool = newEntryPool[K, V]()
+	}
+	if !c.noSweeper {
+		c.sweepTicker = time.NewTicker(c.sweepPeriod)
+		go c.sweep()
+	}
+	return c, nil
 }
 
-func newCacheEntry(key key, value interface{}, exp uint32) *cacheEntry {
-	return &cacheEntry{
+func newCacheEntry[K comparable, V any](key K, value V, exp int64) *cacheEntry[K, V] {
+	return &cacheEntry[K, V]{
 		key:   key,
 		value: value,
 		exp:   exp,
 	}
 }
 
-func (c *TTLCache) Set(key key, value interface{}, optTTL ...time.Duration) error {
-	ttl := c.defaultTTL
-	if len(optTTL) > 0 && optTTL[0] > 0 {
-		ttl = optTTL[0]
+func (c *TTLCache[K, V]) Set(key K, value V, optTTL ...time.Duration) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, err := c.set(key, value, c.writeExp(optTTL))
+	return err
+}
+
+// SetAndGet stores value like Set and returns the resulting entry's metadata from the same operation.
+func (c *TTLCache[K, V]) SetAndGet(key K, value V, optTTL ...time.Duration) (EntryInfo, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entry, err := c.set(key, value, c.writeExp(optTTL))
+	if err != nil {
+		return EntryInfo{}, err
+	}
+	return entry.info(), nil
+}
+
+// SetMany stores every entry with the same TTL under one write lock. Entries are written in map order, which is
+// unspecified, and each write evicts as Set would, so a batch larger than the free space evicts existing
+// entries first and, once those run out, entries written earlier in the batch. It stops at the first error Set
+// would return, keeping the entries written before it.
+func (c *TTLCache[K, V]) SetMany(entries map[K]V, optTTL ...time.Duration) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for k, value := range entries {
+		if _, err := c.set(k, value, c.writeExp(optTTL)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *TTLCache[K, V]) set(key K, value V, exp int64) (*cacheEntry[K, V], error) {
+	return c.write(key, value, exp, false, nil)
+}
+
+// write stores value under key, or a tombstone holding V's zero value if missing is set. parent is the entry
+// SetChild is attaching key to, if any, which the write must not evict to make room.
+func (c *TTLCache[K, V]) write(key K, value V, exp int64, missing bool, parent *cacheEntry[K, V]) (*cacheEntry[K, V], error) {
+	if c.closed {
+		return nil, ErrCacheClosed
+	}
+	if c.maxKeyLen > 0 {
+		if s, ok := keyString(key); ok && len(s) > c.maxKeyLen {
+			return nil, newKeyTooLongErr(len(s), c.maxKeyLen)
+		}
+	}
+
+	var cost int64
+	if !missing {
+		cost = c.cost(value)
+	}
+	entry := c.newEntry(key, value, exp)
+	entry.missing = missing
+
+	if existing, exists := c.cache[key]; exists {
+		//entry only carries the update, so it can go straight back to the pool
+		defer c.release(entry)
+		existing.touch()
+		if c.equalFn != nil && existing.missing == missing && c.equalFn(existing.value, value) {
+			entry.value = existing.value
+			if err := c.updateCacheEntry(entry); err != nil {
+				return nil, err
+			}
+			c.stats.recordSet()
+			return existing, nil
+		}
+		if err := c.reserveBytes(key, cost, existing.cost, existing, parent); err != nil {
+			return nil, err
+		}
+		if err := c.updateCacheEntry(entry); err != nil {
+			return nil, err
+		}
+		c.charge(existing, cost)
+		c.stats.recordSet()
+		existing.version++
+		c.queueEvent(key, ChangeSet, value)
+		return existing, nil
 	}
-	entry := newCacheEntry(key, value, getExp(ttl))
 
-	if _, exists := c.cache[key]; exists {
-		return c.updateCacheEntry(entry)
+	if uint(len(c.cache)) >= c.size {
+		if err := c.makeRoom(key, parent); err != nil {
+			c.release(entry)
+			return nil, err
+		}
+	}
+	if err := c.reserveBytes(key, cost, 0, parent); err != nil {
+		c.release(entry)
+		return nil, err
 	}
 
+	c.charge(entry, cost)
+	entry.createdAt = c.getExp(0)
+	entry.writtenAt = entry.createdAt
+	entry.version = 1
+	entry.touch()
 	c.cache[entry.key] = entry
 	c.insertNewHKEntry(entry)
-	return nil
+	c.stats.recordSet()
+	c.queueEvent(key, ChangeSet, value)
+	return entry, nil
+}
+
+// resolveTTL returns the TTL a write should apply: the provided one if positive or NoExpiry, else the default,
+// plus a fresh random offset WithExpiryJitter. Batch writes call it once per entry so their expiries spread out.
+func (c *TTLCache[K, V]) resolveTTL(optTTL []time.Duration) time.Duration {
+	ttl := c.defaultTTL
+	if len(optTTL) > 0 && (optTTL[0] > 0 || optTTL[0] == NoExpiry) {
+		ttl = optTTL[0]
+	}
+	if ttl == NoExpiry {
+		return NoExpiry
+	}
+	if c.maxJitter > 0 {
+		ttl += time.Duration(rand.Int63n(int64(c.maxJitter)))
+	}
+	return ttl
+}
+
+// writeExp returns the exp a write with optTTL should store.
+func (c *TTLCache[K, V]) writeExp(optTTL []time.Duration) int64 {
+	ttl := c.resolveTTL(optTTL)
+	if ttl == NoExpiry {
+		return neverExpires
+	}
+	return c.getExp(ttl)
 }
 
-func (c *TTLCache) Get(key key) (interface{}, error) {
+// Get returns key's value. An entry is live up to and including the nanosecond its exp names. An expired entry
+// the sweep hasn't reached yet is left to it, unless the cache was created WithCleanupOnGet.
+func (c *TTLCache[K, V]) Get(key K) (V, error) {
+	value, err := c.get(key)
+	c.stats.recordGet(err)
+	return value, err
+}
+
+func (c *TTLCache[K, V]) get(key K) (V, error) {
+	var zero V
+	if c.topK != nil {
+		c.topK.record(key)
+	}
+	if c.slidingTTL {
+		return c.getAndSlide(key)
+	}
+
+	c.mu.RLock()
+	if c.closed {
+		c.mu.RUnlock()
+		return zero, ErrCacheClosed
+	}
 	entry, exists := c.cache[key]
 	if !exists {
-		return nil, newKeyNotFoundErr(key)
+		c.mu.RUnlock()
+		return zero, newKeyNotFoundErr(key)
+	}
+	if !entry.expired(c.getExp(0)) {
+		entry.touch()
+		value, err := entry.result()
+		c.mu.RUnlock()
+		return value, err
+	}
+	c.mu.RUnlock()
+
+	if c.cleanupOnGet {
+		c.removeIfExpired(key)
+	}
+	return zero, newKeyNotFoundErr(key)
+}
+
+// GetWithExpiry returns key's value together with when it expires, read under one lock so the two always
+// belong to the same write. expiresAt is zero for a pinned entry. The lookup counts towards Stats and LRU
+// recency as Get does, but WithSlidingTTL does not extend it.
+func (c *TTLCache[K, V]) GetWithExpiry(key K) (value V, expiresAt time.Time, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return value, expiresAt, ErrCacheClosed
+	}
+	entry, exists := c.cache[key]
+	if !exists || entry.expired(c.getExp(0)) {
+		c.stats.recordMiss()
+		return value, expiresAt, newKeyNotFoundErr(key)
+	}
+	entry.touch()
+	value, err = entry.result()
+	c.stats.recordGet(err)
+	return value, entry.expiresAt(), err
+}
+
+// getAndSlide is Get WithSlidingTTL. Moving the entry in ttlHK needs the write lock, which every Get then takes.
+func (c *TTLCache[K, V]) getAndSlide(key K) (V, error) {
+	var zero V
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.closed {
+		return zero, ErrCacheClosed
+	}
+	entry, exists := c.cache[key]
+	if !exists {
+		return zero, newKeyNotFoundErr(key)
+	}
+	if entry.expired(c.getExp(0)) {
+		if c.cleanupOnGet {
+			c.removeEntry(entry, ChangeExpire)
+		}
+		return zero, newKeyNotFoundErr(key)
+	}
+
+	entry.touch()
+	if exp := c.writeExp(nil); !entry.pinned() && !entry.missing && exp > entry.exp {
+		c.moveHKEntry(entry, exp)
+	}
+	return entry.result()
+}
+
+// Peek returns key's value like Get, but leaves access metadata alone: it doesn't count as a use for
+// EvictLRU or top-K tracking, and never removes an expired entry.
+func (c *TTLCache[K, V]) Peek(key K) (V, error) {
+	var zero V
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return zero, ErrCacheClosed
+	}
+	entry, exists := c.cache[key]
+	if !exists || entry.expired(c.getExp(0)) {
+		return zero, newKeyNotFoundErr(key)
+	}
+	return entry.result()
+}
+
+// Has reports whether key has a live entry, which a SetMissing tombstone is not. Like Peek it leaves access
+// metadata alone, so it never extends a TTL WithSlidingTTL, and it reports false once the cache is closed.
+func (c *TTLCache[K, V]) Has(key K) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return false
+	}
+	entry, exists := c.cache[key]
+	return exists && !entry.expired(c.getExp(0)) && !entry.missing
+}
+
+// removeIfExpired takes the write lock to drop key's entry if it is still expired once the lock is held.
+func (c *TTLCache[K, V]) removeIfExpired(key K) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if entry, exists := c.cache[key]; exists && entry.expired(c.getExp(0)) {
+		c.removeEntry(entry, ChangeExpire)
+	}
+}
+
+// TTL returns how long key's entry has left to live. A pinned entry reports the largest Duration.
+func (c *TTLCache[K, V]) TTL(key K) (time.Duration, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.getExp(0)
+	entry, exists := c.cache[key]
+	if !exists || entry.expired(now) {
+		return 0, newKeyNotFoundErr(key)
+	}
+	if entry.pinned() {
+		return math.MaxInt64, nil
+	}
+	return time.Duration(entry.exp - now), nil
+}
+
+// Delete removes key from the cache, along with any children attached to it with SetChild.
+func (c *TTLCache[K, V]) Delete(key K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entry, exists := c.cache[key]
+	if !exists {
+		return newKeyNotFoundErr(key)
+	}
+
+	c.removeEntry(entry, ChangeDelete)
+	return nil
+}
+
+// Pop removes key and returns its value in one operation, so no other caller can read the entry in between. A
+// SetMissing tombstone is removed too, returning ErrCachedMiss.
+func (c *TTLCache[K, V]) Pop(key K) (V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entry, exists := c.cache[key]
+	if !exists || entry.expired(c.getExp(0)) {
+		var zero V
+		return zero, newKeyNotFoundErr(key)
+	}
+
+	value, err := entry.result()
+	c.removeEntry(entry, ChangeDelete)
+	return value, err
+}
+
+// Clear removes every entry, notifying subscribers of each as a delete, while keeping the cache and its sweeper
+// running. ttlHK keeps its capacity for the entries that follow. Stats and the access counts kept
+// WithTopKTracking describe past traffic rather than contents, so they survive a Clear; call ResetStats to
+// start the counters over.
+func (c *TTLCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for i, entry := range c.ttlHK {
+		c.queueEvent(entry.key, ChangeDelete, entry.value)
+		c.release(entry)
+		c.ttlHK[i] = nil
+	}
+	c.ttlHK = c.ttlHK[:0]
+	c.cache = make(map[K]*cacheEntry[K, V], c.size)
+	c.children = make(map[K]map[K]struct{})
+}
+
+// GetOrStore returns the live value for key with loaded true, or stores defaultValue and returns it with
+// loaded false, like sync.Map's LoadOrStore. A key that Set would reject is not stored. A SetMissing tombstone
+// holds no value, so it is replaced by defaultValue.
+func (c *TTLCache[K, V]) GetOrStore(key K, defaultValue V, optTTL ...time.Duration) (value V, loaded bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if entry, exists := c.cache[key]; exists && !entry.expired(c.getExp(0)) && !entry.missing {
+		return entry.value, true
+	}
+
+	_, _ = c.set(key, defaultValue, c.writeExp(optTTL))
+	return defaultValue, false
+}
+
+// SetNX stores value only if key has no live entry, and reports whether it did. The check and the write happen
+// under one lock, so of several concurrent SetNX calls for the same key exactly one writes. A SetMissing
+// tombstone is not a live entry, so SetNX writes over it.
+func (c *TTLCache[K, V]) SetNX(key K, value V, optTTL ...time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if entry, exists := c.cache[key]; exists && !entry.expired(c.getExp(0)) && !entry.missing {
+		return false, nil
+	}
+
+	if _, err := c.set(key, value, c.writeExp(optTTL)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Replace is the inverse of SetNX: it stores value only if key has a live entry, and reports whether it did. The
+// entry gets the default TTL or the provided one, as with Set. Like SetNX it does not count a SetMissing
+// tombstone as a live entry, so Replace leaves it alone.
+func (c *TTLCache[K, V]) Replace(key K, value V, optTTL ...time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if entry, exists := c.cache[key]; !exists || entry.expired(c.getExp(0)) || entry.missing {
+		return false, nil
+	}
+
+	if _, err := c.set(key, value, c.writeExp(optTTL)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetMissing caches the fact that key has no value, such as a lookup the backend answered with "not found",
+// for the default TTL or the provided one. Until the tombstone expires Get, Peek and GetWithExpiry return
+// ErrCachedMiss for key, GetOrSet returns ErrCachedMiss without loading, and Has reports false. The tombstone
+// takes a slot like any entry and is swept normally. Writers that need a value, such as Update, Migrate and
+// SwapKeys, also return ErrCachedMiss, while SetNX and GetOrStore write over it. Range and EntriesSnapshot see
+// it as an entry holding V's zero value.
+func (c *TTLCache[K, V]) SetMissing(key K, optTTL ...time.Duration) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var zero V
+	_, err := c.write(key, zero, c.writeExp(optTTL), true, nil)
+	return err
+}
+
+// GetFirst returns the first of keys, in the given order, that has a live entry, along with its value. Keys with
+// SetMissing tombstones are skipped.
+func (c *TTLCache[K, V]) GetFirst(keys ...K) (K, V, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.getExp(0)
+	for _, k := range keys {
+		if entry, exists := c.cache[k]; exists && !entry.expired(now) && !entry.missing {
+			return k, entry.value, nil
+		}
+	}
+	var zeroKey K
+	var zero V
+	return zeroKey, zero, newNoKeysFoundErr(keys)
+}
+
+// GetMany returns the live values among keys and the keys that missed, including those with SetMissing
+// tombstones, looked up under one read lock. Hits count towards Stats and LRU recency as they would for Get, but
+// WithSlidingTTL does not extend them.
+func (c *TTLCache[K, V]) GetMany(keys []K) (map[K]V, []K) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.getExp(0)
+	values := make(map[K]V, len(keys))
+	var missing []K
+	for _, k := range keys {
+		entry, exists := c.cache[k]
+		if !exists || entry.expired(now) || entry.missing {
+			c.stats.recordMiss()
+			missing = append(missing, k)
+			continue
+		}
+		entry.touch()
+		c.stats.recordHit()
+		values[k] = entry.value
+	}
+	return values, missing
+}
+
+// SweepTick removes every entry that has expired, for hosts that drive sweeps on their own schedule. Pair it
+// with WithExternalSweep so the cache doesn't also sweep in the background.
+func (c *TTLCache[K, V]) SweepTick() {
+	c.TriggerSweep()
+}
+
+// TriggerSweep runs the background sweep now and returns the number of entries it removed, counting any
+// the PressureFunc evicted along with the expired ones.
+func (c *TTLCache[K, V]) TriggerSweep() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.evict(c.getExp(0))
+}
+
+// sweep runs SweepTick on every tick of sweepTicker until the cache is closed.
+func (c *TTLCache[K, V]) sweep() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.sweepTicker.C:
+			c.SweepTick()
+		}
 	}
+}
+
+// Close stops the background sweep, if there is one. Set and Get return ErrCacheClosed afterwards. Calling
+// Close more than once is safe.
+func (c *TTLCache[K, V]) Close() error {
+	c.closeOnce.Do(func() {
+		if c.sweepTicker != nil {
+			c.sweepTicker.Stop()
+		}
+		close(c.done)
 
-	return entry.value, nil
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+	})
+	return nil
 }
 
-func (c *TTLCache) evict(exp uint32) {
+// evict removes the entries expired by exp and any the PressureFunc asks for, returning how many it removed.
+func (c *TTLCache[K, V]) evict(exp int64) int {
+	before := len(c.cache)
+	if c.selfHealing && !c.indexSorted() {
+		c.rebuildIndex()
+	}
+
 	indexOfLastEvicted := c.evictFromCoreCache(exp)
 	if indexOfLastEvicted >= 0 {
+		evicted := c.ttlHK[:indexOfLastEvicted+1]
 		c.ttlHK = c.ttlHK[indexOfLastEvicted+1:]
+		for _, entry := range evicted {
+			c.detach(entry, ChangeExpire)
+			c.release(entry)
+		}
+	}
+	c.evictUnderPressure(exp)
+	return before - len(c.cache)
+}
+
+// RebuildIndex re-sorts ttlHK by ascending expiry.
+func (c *TTLCache[K, V]) RebuildIndex() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rebuildIndex()
+}
+
+func (c *TTLCache[K, V]) rebuildIndex() {
+	sort.SliceStable(c.ttlHK, func(i, j int) bool {
+		return c.ttlHK[i].exp < c.ttlHK[j].exp
+	})
+}
+
+// Normalize reconciles ttlHK with the map after direct mutation: index entries the map doesn't hold are
+// dropped, map entries missing from the index are added, and the index is re-sorted.
+func (c *TTLCache[K, V]) Normalize() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	indexed := make(map[*cacheEntry[K, V]]struct{}, len(c.ttlHK))
+	kept := c.ttlHK[:0]
+	for _, entry := range c.ttlHK {
+		if _, dup := indexed[entry]; dup || c.cache[entry.key] != entry {
+			continue
+		}
+		indexed[entry] = struct{}{}
+		kept = append(kept, entry)
+	}
+	for i := len(kept); i < len(c.ttlHK); i++ {
+		c.ttlHK[i] = nil
+	}
+
+	for _, entry := range c.cache {
+		if _, exists := indexed[entry]; !exists {
+			kept = append(kept, entry)
+		}
+	}
+	c.ttlHK = kept
+	c.rebuildIndex()
+}
+
+func (c *TTLCache[K, V]) indexSorted() bool {
+	for i := 1; i < len(c.ttlHK); i++ {
+		if c.ttlHK[i-1].exp > c.ttlHK[i].exp {
+			return false
+		}
+	}
+	return true
+}
+
+// unlock releases c.mu, then delivers the change events queued while it was held.
+func (c *TTLCache[K, V]) unlock() {
+	c.releaseFreed()
+	events := c.pendingEvents
+	c.pendingEvents = nil
+	c.mu.Unlock()
+	c.deliverEvents(events)
+}
+
+// removeEntry deletes entry from both the map and ttlHK, notifying subscribers and removing its children.
+func (c *TTLCache[K, V]) removeEntry(entry *cacheEntry[K, V], changeType ChangeType) {
+	delete(c.cache, entry.key)
+	c.removeHKEntry(entry)
+	c.queueEvent(entry.key, changeType, entry.value)
+	c.detach(entry, changeType)
+	c.release(entry)
+}
+
+func (c *TTLCache[K, V]) removeHKEntry(entry *cacheEntry[K, V]) {
+	i := c.indexOfHKEntry(entry)
+	if i < 0 {
+		return
+	}
+	copy(c.ttlHK[i:], c.ttlHK[i+1:])
+	c.ttlHK[len(c.ttlHK)-1] = nil
+	c.ttlHK = c.ttlHK[:len(c.ttlHK)-1]
+}
+
+func (c *TTLCache[K, V]) indexOfHKEntry(entry *cacheEntry[K, V]) int {
+	i := sort.Search(len(c.ttlHK), func(i int) bool {
+		return c.ttlHK[i].exp >= entry.exp
+	})
+	for ; i < len(c.ttlHK) && c.ttlHK[i].exp == entry.exp; i++ {
+		if c.ttlHK[i] == entry {
+			return i
+		}
 	}
+	return -1
 }
 
-func (c *TTLCache) evictFromCoreCache(exp uint32) int {
+func (c *TTLCache[K, V]) evictFromCoreCache(exp int64) int {
 	indexOfLastEvicted := -1
 	for i, cacheEntry := range c.ttlHK {
 		if cacheEntry.exp < exp {
 			delete(c.cache, cacheEntry.key)
+			c.queueEvent(cacheEntry.key, ChangeExpire, cacheEntry.value)
 			indexOfLastEvicted = i
 			continue
 		}
@@ -94,33 +730,78 @@ func (c *TTLCache) evictFromCoreCache(exp uint32) int {
 	return indexOfLastEvicted
 }
 
-//Possible optimization: use Sort.Search to find new location for entry; use multiple copy() to remove existing entry and re-add
-//Benchmark it
-func (c *TTLCache) updateCacheEntry(entry *cacheEntry) error {
+// updateCacheEntry copies entry's value and exp onto the stored entry for its key. Only that entry moves in
+// ttlHK: it is found by binary search and removed, then reinserted at its new position, which is O(n) rather
+// than a full re-sort.
+func (c *TTLCache[K, V]) updateCacheEntry(entry *cacheEntry[K, V]) error {
 	existingValue, exists := c.cache[entry.key]
 	if !exists {
 		return newBadUpdateRequestErr(entry.key)
 	}
 
 	existingValue.value = entry.value
-	existingValue.exp = entry.exp
-
-	sort.Slice(c.ttlHK, func(i, j int) bool {
-		return c.ttlHK[i].exp >= c.ttlHK[i].exp
-	})
+	existingValue.missing = entry.missing
+	//moveHKEntry matches the stored entry itself, so entries sharing its exp stay put
+	c.moveHKEntry(existingValue, entry.exp)
 
 	return nil
 }
 
-func (c *TTLCache) insertNewHKEntry(entry *cacheEntry) {
+func (c *TTLCache[K, V]) insertNewHKEntry(entry *cacheEntry[K, V]) {
 	i := sort.Search(len(c.ttlHK), func(i int) bool {
 		return c.ttlHK[i].exp >= entry.exp
 	})
-	c.ttlHK = append(c.ttlHK, &cacheEntry{})
+	c.ttlHK = append(c.ttlHK, &cacheEntry[K, V]{})
 	copy(c.ttlHK[i+1:], c.ttlHK[i:])
 	c.ttlHK[i] = entry
 }
 
-func getExp(ttl time.Duration) uint32 {
-	return uint32(time.Now().Add(ttl).Unix())
+func (e *cacheEntry[K, V]) expired(now int64) bool {
+	return e.exp < now
+}
+
+// lookup returns key's live entry for an operation that needs its value. A SetMissing tombstone has none, so it
+// is reported as ErrCachedMiss.
+func (c *TTLCache[K, V]) lookup(key K) (*cacheEntry[K, V], error) {
+	entry, exists := c.cache[key]
+	if !exists || entry.expired(c.getExp(0)) {
+		return nil, newKeyNotFoundErr(key)
+	}
+	if entry.missing {
+		return nil, ErrCachedMiss
+	}
+	return entry, nil
+}
+
+// result is what a read of the live entry returns: its value, or ErrCachedMiss for a tombstone.
+func (e *cacheEntry[K, V]) result() (V, error) {
+	if e.missing {
+		var zero V
+		return zero, ErrCachedMiss
+	}
+	return e.value, nil
+}
+
+func (e *cacheEntry[K, V]) expiresAt() time.Time {
+	if e.pinned() {
+		return time.Time{}
+	}
+	return time.Unix(0, e.exp)
+}
+
+func (e *cacheEntry[K, V]) info() EntryInfo {
+	return EntryInfo{
+		ExpiresAt: e.expiresAt(),
+		CreatedAt: time.Unix(0, e.createdAt),
+		Version:   e.version,
+	}
+}
+
+// getExp returns the exp of an entry stored now with ttl. It reads the wall clock unless the cache was created
+// WithMonotonicClock.
+func (c *TTLCache[K, V]) getExp(ttl time.Duration) int64 {
+	if !c.monotonic {
+		return c.now().Add(ttl).UnixNano()
+	}
+	return c.monoBase.Add(c.since(c.monoBase)).Add(ttl).UnixNano()
 }
@@ -1,6 +1,12 @@
 package ttl_cache
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,11 +15,14 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+// key is the key type the tests instantiate caches with.
+type key string
+
 type cacheSuite struct {
 	size        uint
 	defaultTTL  time.Duration
 	sweepPeriod time.Duration
-	cache       *TTLCache
+	cache       *TTLCache[key, interface{}]
 	suite.Suite
 }
 
@@ -29,64 +38,71 @@ func (cs *cacheSuite) SetupSuite() {
 	if cs.sweepPeriod == 0 {
 		cs.sweepPeriod = 5 * time.Second
 	}
-	cs.cache, err = NewTTLCache(cs.size, cs.defaultTTL, cs.sweepPeriod)
+	cs.cache, err = NewTTLCache[key, interface{}](WithSize(cs.size), WithDefaultTTL(cs.defaultTTL), WithSweepPeriod(cs.sweepPeriod))
 	require.Nil(cs.T(), err)
 }
 
-//TestCases
-//-Success
-//--Normal Success
+// TestCases
+// -Success
+// --Normal Success
+// --Defaults when no options are given
 //
-//-Error
-//--Sweep Period = 0
-//--TTL = 0
-//--NumSize = 0?
+// -Error
+// --Sweep Period = 0
+// --TTL = 0
+// --NumSize = 0?
 func TestNewTTLCache_Creation(t *testing.T) {
 	type tc struct {
 		description   string
-		numSize       uint
-		defaultTTL    time.Duration
-		sweepPeriod   time.Duration
-		expectedCache *TTLCache
+		opts          []Option
+		expectedCache *TTLCache[key, interface{}]
 		expectedErr   error
 	}
 
 	tcs := []tc{
 		{
 			description: "normal success",
-			numSize:     10,
-			defaultTTL:  30 * time.Second,
-			sweepPeriod: 5 * time.Second,
-			expectedCache: &TTLCache{
-				defaultTTL:  30 * time.Second,
+			opts:        []Option{WithSize(10), WithDefaultTTL(30 * time.Second), WithSweepPeriod(5 * time.Second)},
+			expectedCache: &TTLCache[key, interface{}]{
+				options: options{
+					size:        10,
+					defaultTTL:  30 * time.Second,
+					sweepPeriod: 5 * time.Second,
+				},
 				sweepTicker: time.NewTicker(5 * time.Second),
-				cache:       make(map[key]*cacheEntry, 10),
-				ttlHK:       make([]*cacheEntry, 0, 10),
-				size:        10,
+				cache:       make(map[key]*cacheEntry[key, interface{}], 10),
+				ttlHK:       make([]*cacheEntry[key, interface{}], 0, 10),
+			},
+			expectedErr: nil,
+		},
+		{
+			description: "defaults",
+			expectedCache: &TTLCache[key, interface{}]{
+				options: options{
+					size:        DefaultSize,
+					defaultTTL:  DefaultTTL,
+					sweepPeriod: DefaultSweepPeriod,
+				},
+				cache: make(map[key]*cacheEntry[key, interface{}], DefaultSize),
+				ttlHK: make([]*cacheEntry[key, interface{}], 0, DefaultSize),
 			},
 			expectedErr: nil,
 		},
 		{
 			description:   "error - Sweep period <= 0s",
-			numSize:       10,
-			defaultTTL:    30 * time.Second,
-			sweepPeriod:   0 * time.Second,
+			opts:          []Option{WithSweepPeriod(0 * time.Second)},
 			expectedCache: nil,
 			expectedErr:   newInvalidSweepPeriodErr(0 * time.Second),
 		},
 		{
 			description:   "error - TTL <= 0s",
-			numSize:       10,
-			defaultTTL:    0 * time.Second,
-			sweepPeriod:   5 * time.Second,
+			opts:          []Option{WithDefaultTTL(0 * time.Second)},
 			expectedCache: nil,
 			expectedErr:   newInvalidTTLErr(0 * time.Second),
 		},
 		{
 			description:   "error - numSize <= 0",
-			numSize:       0,
-			defaultTTL:    30 * time.Second,
-			sweepPeriod:   5 * time.Second,
+			opts:          []Option{WithSize(0)},
 			expectedCache: nil,
 			expectedErr:   newInvalidSizeErr(0),
 		},
@@ -94,14 +110,365 @@ func TestNewTTLCache_Creation(t *testing.T) {
 
 	for _, testCase := range tcs {
 		t.Run(testCase.description, func(t *testing.T) {
-			cache, err := NewTTLCache(testCase.numSize, testCase.defaultTTL, testCase.sweepPeriod)
+			cache, err := NewTTLCache[key, interface{}](testCase.opts...)
 			assertCachesAreEqual(t, testCase.expectedCache, cache)
 			assert.Equal(t, testCase.expectedErr, err)
+			if cache != nil {
+				require.Nil(t, cache.Close())
+			}
 		})
 	}
 }
 
-//TODO NewTTLCache_StartsTicker
+// TestCases
+// -Success
+// --Sweep goroutine removes only expired entries
+func TestNewTTLCache_Sweeps(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(100*time.Millisecond))
+	require.Nil(t, err)
+
+	require.Nil(t, cache.Set(key("short"), "value", time.Second))
+	require.Nil(t, cache.Set(key("medium"), "value", 3*time.Second))
+	require.Nil(t, cache.Set(key("long"), "value", time.Hour))
+
+	assert.Eventually(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		_, exists := cache.cache[key("short")]
+		return !exists
+	}, 3*time.Second, 50*time.Millisecond)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	assertCacheHasNKeys(t, 2, cache)
+	assert.Contains(t, cache.cache, key("medium"))
+	assert.Contains(t, cache.cache, key("long"))
+}
+
+// TestCases
+// -Success
+// --Sweep goroutine exits
+// --Close is idempotent
+//
+// -Error
+// --Set, Get and Has after Close
+func TestCache_Close(t *testing.T) {
+	before := runtime.NumGoroutine()
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("key"), "value"))
+	assert.True(t, runtime.NumGoroutine() > before)
+
+	assert.Nil(t, cache.Close())
+	assert.Nil(t, cache.Close())
+	//Poll by hand: assert.Eventually runs its condition on a goroutine of its own
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, before, runtime.NumGoroutine())
+
+	assert.Equal(t, ErrCacheClosed, cache.Set(key("key"), "value"))
+	value, err := cache.Get(key("key"))
+	assert.Nil(t, value)
+	assert.Equal(t, ErrCacheClosed, err)
+	assert.False(t, cache.Has(key("key")))
+}
+
+// TestCases
+// -Success
+// --WithNoSweeper starts no goroutine and accepts a zero sweep period
+// --Get and Has still treat expired entries as missing
+// --Close is a safe no-op
+// --WithExternalSweep starts no goroutine either, leaving SweepTick to reap
+func TestCache_NoSweeper(t *testing.T) {
+	before := runtime.NumGoroutine()
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(0), WithNoSweeper())
+	require.Nil(t, err)
+	assert.Nil(t, cache.sweepTicker)
+	assert.True(t, runtime.NumGoroutine() <= before)
+	assert.Equal(t, time.Duration(0), cache.Config().SweepPeriod)
+	assert.True(t, cache.Config().NoSweeper)
+
+	require.Nil(t, cache.Set(key("expired"), "value"))
+	cache.moveHKEntry(cache.cache[key("expired")], getExp(-time.Second))
+	assertKeyDoesNotExist(t, key("expired"), cache)
+	assert.False(t, cache.Has(key("expired")))
+	//Nothing reaps it until asked
+	assertCacheHasNKeys(t, 1, cache)
+	assert.Equal(t, 1, cache.TriggerSweep())
+
+	assert.NotPanics(t, func() {
+		assert.Nil(t, cache.Close())
+		assert.Nil(t, cache.Close())
+	})
+	assert.Equal(t, ErrCacheClosed, cache.Set(key("key"), "value"))
+
+	before = runtime.NumGoroutine()
+	external, err := NewTTLCache[key, interface{}](WithExternalSweep())
+	require.Nil(t, err)
+	assert.Nil(t, external.sweepTicker)
+	assert.True(t, runtime.NumGoroutine() <= before)
+	assert.True(t, external.Config().NoSweeper)
+	require.Nil(t, external.Set(key("expired"), "value"))
+	external.moveHKEntry(external.cache[key("expired")], getExp(-time.Second))
+	external.SweepTick()
+	assertCacheHasNKeys(t, 0, external)
+}
+
+// TestCases
+// -Success
+// --Concurrent Set, Get and sweeps on overlapping keys leave a consistent cache
+func TestCache_Concurrent(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(time.Millisecond))
+	require.Nil(t, err)
+	defer cache.Close()
+
+	keys := []key{"a", "b", "c", "d", "e"}
+	var wg sync.WaitGroup
+	for g := 0; g < 48; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				k := keys[(g+i)%len(keys)]
+				if g%2 == 0 {
+					assert.Nil(t, cache.Set(k, i, time.Duration(1+i%3)*time.Second))
+					continue
+				}
+				if _, err := cache.Get(k); err != nil {
+					assert.IsType(t, newKeyNotFoundErr(k), err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	assert.Nil(t, cache.checkInvariants())
+}
+
+// TestCases
+// -Success
+// --A backward wall-clock step extends entries in a wall-clock cache
+// --A monotonic cache expires entries on time regardless of the step
+func TestCache_MonotonicClock(t *testing.T) {
+	//Step the wall clock back an hour while three seconds pass on the monotonic clock
+	start := time.Now()
+	var elapsed time.Duration
+	stepped := func(cache *TTLCache[key, interface{}]) {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		cache.now = func() time.Time {
+			return start.Add(elapsed - time.Hour)
+		}
+		cache.since = func(time.Time) time.Duration {
+			return elapsed
+		}
+	}
+
+	wall, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	monotonic, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithMonotonicClock())
+	require.Nil(t, err)
+	require.Nil(t, wall.Set(key("key"), "value", time.Second))
+	require.Nil(t, monotonic.Set(key("key"), "value", time.Second))
+
+	elapsed = 3 * time.Second
+	stepped(wall)
+	stepped(monotonic)
+	wall.SweepTick()
+	monotonic.SweepTick()
+
+	assertKeyMapsToValue(t, "value", key("key"), wall)
+	assertKeyDoesNotExist(t, key("key"), monotonic)
+}
+
+// TestCases
+// -Success
+// --Fresh entry has the default TTL left
+// --Entry set with optTTL has that TTL left
+// --Pinned entry has the largest Duration left
+//
+// -Error
+// --Missing key
+// --Expired but unswept key
+func TestCache_TTL(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("default"), "value"))
+	require.Nil(t, cache.Set(key("custom"), "value", 90*time.Second))
+	require.Nil(t, cache.Set(key("pinned"), "value"))
+	require.Nil(t, cache.Pin(key("pinned")))
+	require.Nil(t, cache.Set(key("expired"), "value"))
+	cache.moveHKEntry(cache.cache[key("expired")], getExp(-time.Second))
+
+	ttl, err := cache.TTL(key("default"))
+	assert.Nil(t, err)
+	assert.InDelta(t, 30*time.Second, ttl, float64(time.Second))
+
+	ttl, err = cache.TTL(key("custom"))
+	assert.Nil(t, err)
+	assert.InDelta(t, 90*time.Second, ttl, float64(time.Second))
+
+	ttl, err = cache.TTL(key("pinned"))
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(math.MaxInt64), ttl)
+
+	for _, k := range []key{"missing", "expired"} {
+		ttl, err = cache.TTL(k)
+		assert.Equal(t, newKeyNotFoundErr(k), err)
+		assert.Zero(t, ttl)
+	}
+}
+
+// TestCases
+// -Success
+// --Fixed TTL expires on schedule however often the entry is read
+// --Sliding TTL keeps a regularly read entry alive
+// --Peek never slides the TTL
+func TestCache_SlidingTTL(t *testing.T) {
+	start := time.Now()
+	newCache := func(opts ...Option) (*TTLCache[key, interface{}], *time.Duration) {
+		cache, err := NewTTLCache[key, interface{}](append([]Option{WithDefaultTTL(30 * time.Second)}, opts...)...)
+		require.Nil(t, err)
+		elapsed := new(time.Duration)
+		cache.now = func() time.Time {
+			return start.Add(*elapsed)
+		}
+		require.Nil(t, cache.Set(key("session"), "user"))
+		require.Nil(t, cache.Set(key("peeked"), "user"))
+		return cache, elapsed
+	}
+	fixed, fixedElapsed := newCache()
+	sliding, slidingElapsed := newCache(WithSlidingTTL())
+
+	//Read every 20s, past the 30s TTL
+	for _, at := range []time.Duration{20 * time.Second, 40 * time.Second, 60 * time.Second} {
+		*fixedElapsed, *slidingElapsed = at, at
+		_, fixedErr := fixed.Get(key("session"))
+		value, slidingErr := sliding.Get(key("session"))
+		_, peekErr := sliding.Peek(key("peeked"))
+
+		assert.Nil(t, slidingErr, "sliding at %s", at)
+		assert.Equal(t, "user", value)
+		if at < 30*time.Second {
+			assert.Nil(t, fixedErr)
+			assert.Nil(t, peekErr)
+		} else {
+			assert.Equal(t, newKeyNotFoundErr(key("session")), fixedErr, "fixed at %s", at)
+			assert.Equal(t, newKeyNotFoundErr(key("peeked")), peekErr, "peek at %s", at)
+		}
+	}
+	assert.Equal(t, start.Add(90*time.Second).UnixNano(), sliding.cache[key("session")].exp)
+	assert.Nil(t, sliding.checkInvariants())
+}
+
+// TestCases
+// -Success
+// --Sub-second TTL is alive before it elapses and gone after
+func TestCache_SubSecondTTL(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	k := key("short")
+	require.Nil(t, cache.Set(k, "value", 250*time.Millisecond))
+
+	time.Sleep(100 * time.Millisecond)
+	value, err := cache.Get(k)
+	assert.Nil(t, err)
+	assert.Equal(t, "value", value)
+
+	time.Sleep(200 * time.Millisecond)
+	value, err = cache.Get(k)
+	assert.Nil(t, value)
+	assert.Equal(t, newKeyNotFoundErr(k), err)
+}
+
+// TestCases
+// -Success
+// --Expiries of entries set together are spread across the jitter window
+// --Touch draws a new offset
+func TestCache_ExpiryJitter(t *testing.T) {
+	ttl, jitter := 30*time.Second, 10*time.Second
+	cache, err := NewTTLCache[key, interface{}](WithSize(100), WithDefaultTTL(ttl), WithExpiryJitter(jitter))
+	require.Nil(t, err)
+
+	entries := make(map[key]interface{}, 100)
+	for i := 0; i < 100; i++ {
+		entries[key(fmt.Sprintf("key%d", i))] = i
+	}
+	before := getExp(ttl)
+	require.Nil(t, cache.SetMany(entries))
+	after := getExp(ttl + jitter)
+
+	exps := make(map[int64]struct{}, len(entries))
+	var earliest, latest int64 = math.MaxInt64, 0
+	for _, entry := range cache.cache {
+		assert.GreaterOrEqual(t, entry.exp, before)
+		assert.Less(t, entry.exp, after)
+		exps[entry.exp] = struct{}{}
+		if entry.exp < earliest {
+			earliest = entry.exp
+		}
+		if entry.exp > latest {
+			latest = entry.exp
+		}
+	}
+	assert.Greater(t, len(exps), 90)
+	assert.Greater(t, latest-earliest, int64(jitter/2))
+
+	touched := cache.cache[key("key0")]
+	exp := touched.exp
+	require.Nil(t, cache.Touch(key("key0")))
+	assert.NotEqual(t, exp, touched.exp)
+	assert.Nil(t, cache.checkInvariants())
+}
+
+// TestCases
+// -Success
+// --String keys with struct values need no type assertions
+// --Int keys with struct values need no type assertions
+//
+// -Error
+// --Miss returns the zero value
+func TestTTLCache_Generic(t *testing.T) {
+	type session struct {
+		user  string
+		roles []string
+	}
+
+	t.Run("string keys", func(t *testing.T) {
+		cache, err := NewTTLCache[string, session](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+		require.Nil(t, err)
+		require.Nil(t, cache.Set("token", session{user: "amy", roles: []string{"admin"}}))
+
+		s, err := cache.Get("token")
+		assert.Nil(t, err)
+		assert.Equal(t, "amy", s.user)
+		assert.Equal(t, []string{"admin"}, s.roles)
+
+		s, err = cache.Get("missing")
+		assert.Equal(t, newKeyNotFoundErr("missing"), err)
+		assert.Zero(t, s)
+	})
+
+	t.Run("int keys", func(t *testing.T) {
+		cache, err := NewTTLCache[int, *session](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+		require.Nil(t, err)
+		require.Nil(t, cache.Set(10, &session{user: "ten"}))
+		require.Nil(t, cache.Set(9, &session{user: "nine"}, time.Minute))
+
+		s, err := cache.Get(10)
+		assert.Nil(t, err)
+		assert.Equal(t, "ten", s.user)
+		assert.Equal(t, []int{10, 9}, cache.Keys())
+
+		s, err = cache.Get(11)
+		assert.Equal(t, newKeyNotFoundErr(11), err)
+		assert.Nil(t, s)
+	})
+}
 
 func TestNewCacheEntry(t *testing.T) {
 	type testVal struct {
@@ -121,8 +488,8 @@ func TestNewCacheEntry(t *testing.T) {
 		description   string
 		key           key
 		value         interface{}
-		exp           uint32
-		expectedEntry *cacheEntry
+		exp           int64
+		expectedEntry *cacheEntry[key, interface{}]
 	}
 
 	tcs := []tc{
@@ -131,7 +498,7 @@ func TestNewCacheEntry(t *testing.T) {
 			key:         key("int"),
 			value:       testValInt,
 			exp:         12345,
-			expectedEntry: &cacheEntry{
+			expectedEntry: &cacheEntry[key, interface{}]{
 				key:   key("int"),
 				value: testValInt,
 				exp:   12345,
@@ -142,7 +509,7 @@ func TestNewCacheEntry(t *testing.T) {
 			key:         key("string"),
 			value:       testValString,
 			exp:         67890,
-			expectedEntry: &cacheEntry{
+			expectedEntry: &cacheEntry[key, interface{}]{
 				key:   key("string"),
 				value: testValString,
 				exp:   67890,
@@ -153,7 +520,7 @@ func TestNewCacheEntry(t *testing.T) {
 			key:         key("struct"),
 			value:       testValStruct,
 			exp:         45678,
-			expectedEntry: &cacheEntry{
+			expectedEntry: &cacheEntry[key, interface{}]{
 				key:   key("struct"),
 				value: testValStruct,
 				exp:   45678,
@@ -164,7 +531,7 @@ func TestNewCacheEntry(t *testing.T) {
 			key:         key("struct"),
 			value:       testValPointer,
 			exp:         12390,
-			expectedEntry: &cacheEntry{
+			expectedEntry: &cacheEntry[key, interface{}]{
 				key:   key("struct"),
 				value: testValPointer,
 				exp:   12390,
@@ -174,20 +541,20 @@ func TestNewCacheEntry(t *testing.T) {
 
 	for _, testCase := range tcs {
 		t.Run(testCase.description, func(t *testing.T) {
-			assert.Equal(t, testCase.expectedEntry, newCacheEntry(testCase.key, testCase.value, testCase.exp))
+			assert.Equal(t, testCase.expectedEntry, newCacheEntry[key, interface{}](testCase.key, testCase.value, testCase.exp))
 		})
 	}
 }
 
-//TestCases
-//-Success
-//--New Entry correctly sorted
-//--Existing Entry - Overwrite and update TTL
-//--Full cache calls evict -- TODO
-//--Add/Update to Cache is concurrent safe -- TODO
+// TestCases
+// -Success
+// --New Entry correctly sorted
+// --Existing Entry - Overwrite and update TTL
+// --Full cache calls evict
+// --Overwriting a key in a full cache evicts nothing
 //
-//-Error
-//--Cache is full after evict-- TODO
+// -Error
+// --Cache is full after evict
 func TestTTLCache_Set(t *testing.T) {
 	css := new(setSuite)
 	suite.Run(t, css)
@@ -212,14 +579,14 @@ func (css *setSuite) TestCache_Set_NewEntry() {
 
 	//Ensure new entry added to cache
 	assert.Equal(css.T(), expectedLen, len(css.cache.cache))
-	expectedEntry := newCacheEntry(keyOfEarlyExp, earlyExpVal, getExp(css.cache.defaultTTL))
+	expectedEntry := newCacheEntry[key, interface{}](keyOfEarlyExp, earlyExpVal, getExp(css.cache.defaultTTL))
 	actualEntry, exists := css.cache.cache[keyOfEarlyExp]
 	assert.True(css.T(), exists)
-	assert.Equal(css.T(), expectedEntry, actualEntry)
+	assertEntriesMatch(css.T(), expectedEntry, actualEntry)
 
 	//Ensure new entry added to housekeeping slice
 	assert.Equal(css.T(), expectedLen, len(css.cache.ttlHK))
-	assert.Equal(css.T(), expectedEntry, css.cache.ttlHK[0])
+	assertEntriesMatch(css.T(), expectedEntry, css.cache.ttlHK[0])
 
 	keyOfLaterExp := key("second")
 	laterExpVal := "second"
@@ -230,14 +597,51 @@ func (css *setSuite) TestCache_Set_NewEntry() {
 
 	//Ensure new entry added to cache with correct TTL
 	assert.Equal(css.T(), expectedLen, len(css.cache.cache))
-	expectedEntry = newCacheEntry(keyOfLaterExp, laterExpVal, getExp(optTTL))
+	expectedEntry = newCacheEntry[key, interface{}](keyOfLaterExp, laterExpVal, getExp(optTTL))
 	actualEntry, exists = css.cache.cache[keyOfLaterExp]
 	assert.True(css.T(), exists)
-	assert.Equal(css.T(), expectedEntry, actualEntry)
+	assertEntriesMatch(css.T(), expectedEntry, actualEntry)
 
 	//Ensure new entry added to housekeeping slice in correct place
 	assert.Equal(css.T(), expectedLen, len(css.cache.ttlHK))
-	assert.Equal(css.T(), expectedEntry, css.cache.ttlHK[1])
+	assertEntriesMatch(css.T(), expectedEntry, css.cache.ttlHK[1])
+}
+
+func (css *setSuite) TestCache_Set_FullCacheEvicts() {
+	cache, err := NewTTLCache[key, interface{}](WithSize(2), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(css.T(), err)
+	require.Nil(css.T(), cache.Set(key("later"), "value", time.Minute))
+	require.Nil(css.T(), cache.Set(key("soonest"), "value", time.Second))
+	events, unsubscribe := cache.Subscribe(key("soonest"), 1)
+	defer unsubscribe()
+
+	//Ensure the entry expiring soonest makes room
+	require.Nil(css.T(), cache.Set(key("new"), "value"))
+	assertCacheHasNKeys(css.T(), 2, cache)
+	assertKeyDoesNotExist(css.T(), key("soonest"), cache)
+	assertKeyMapsToValue(css.T(), "value", key("later"), cache)
+	assertKeyMapsToValue(css.T(), "value", key("new"), cache)
+	assert.Equal(css.T(), ChangeEvent[interface{}]{Type: ChangeEvict, Value: "value"}, <-events)
+
+	//Ensure overwriting an existing key needs no room
+	require.Nil(css.T(), cache.Set(key("later"), "overwritten"))
+	assertCacheHasNKeys(css.T(), 2, cache)
+	assertKeyMapsToValue(css.T(), "value", key("new"), cache)
+}
+
+func (css *setSuite) TestCache_Set_FullAfterEvict() {
+	cache, err := NewTTLCache[key, interface{}](WithSize(2), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(css.T(), err)
+	require.Nil(css.T(), cache.Set(key("first"), "value"))
+	require.Nil(css.T(), cache.Set(key("second"), "value"))
+	require.Nil(css.T(), cache.Pin(key("first")))
+	require.Nil(css.T(), cache.Pin(key("second")))
+
+	//Ensure pinned entries are never evicted to make room
+	err = cache.Set(key("rejected"), "value")
+	assert.Equal(css.T(), newCacheFullErr(key("rejected")), err)
+	assertCacheHasNKeys(css.T(), 2, cache)
+	assertKeyDoesNotExist(css.T(), key("rejected"), cache)
 }
 
 func (css *setSuite) TestCache_Set_OverwriteExisting() {
@@ -259,14 +663,300 @@ func (css *setSuite) TestCache_Set_OverwriteExisting() {
 	assert.Equal(css.T(), expectedLen, len(css.cache.ttlHK))
 }
 
+// TestCases
+// -Success
+// --New entry reports expiry from the applied TTL and version 1
+// --Overwrite increments the version and keeps createdAt
+func TestCache_SetAndGet(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	k := key("key")
+
+	info, err := cache.SetAndGet(k, "first")
+	require.Nil(t, err)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), info.ExpiresAt, time.Second)
+	assert.WithinDuration(t, time.Now(), info.CreatedAt, time.Second)
+	assert.Equal(t, uint64(1), info.Version)
+
+	overwriteTTL := 60 * time.Second
+	overwritten, err := cache.SetAndGet(k, "second", overwriteTTL)
+	require.Nil(t, err)
+	assert.WithinDuration(t, time.Now().Add(overwriteTTL), overwritten.ExpiresAt, time.Second)
+	assert.Equal(t, info.CreatedAt, overwritten.CreatedAt)
+	assert.Equal(t, uint64(2), overwritten.Version)
+	assertKeyMapsToValue(t, "second", k, cache)
+}
+
+// TestCases
+// -Success
+// --Equal value refreshes the TTL without a change event
+// --Different value is written and notified
+func TestCache_Set_SkipEqualWrites(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithSkipEqualWrites(func(a, b interface{}) bool {
+		return a == b
+	}))
+	require.Nil(t, err)
+	k := key("key")
+	require.Nil(t, cache.Set(k, "value", 5*time.Second))
+
+	events, unsubscribe := cache.Subscribe(k, 10)
+	defer unsubscribe()
+
+	require.Nil(t, cache.Set(k, "value", 60*time.Second))
+	assertNoEvent(t, events)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[k].exp)
+	assert.Equal(t, uint64(1), cache.cache[k].version)
+
+	require.Nil(t, cache.Set(k, "other"))
+	assert.Equal(t, ChangeEvent[interface{}]{Type: ChangeSet, Value: "other"}, <-events)
+	assert.Equal(t, uint64(2), cache.cache[k].version)
+}
+
+// TestCases
+// -Success
+// --Key within the limit is stored
+//
+// -Error
+// --Key over the limit is rejected and the cache is unchanged
+func TestCache_Set_MaxKeyLength(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithMaxKeyLength(5))
+	require.Nil(t, err)
+
+	err = cache.Set(key("short"), "value")
+	assert.Nil(t, err)
+	assertKeyMapsToValue(t, "value", key("short"), cache)
+
+	err = cache.Set(key("toolong"), "value")
+	assert.True(t, errors.Is(err, ErrKeyTooLong))
+	assert.Equal(t, newKeyTooLongErr(7, 5), err)
+	assertKeyDoesNotExist(t, key("toolong"), cache)
+	assertCacheHasNKeys(t, 1, cache)
+}
+
+// TestCases
+// -Success
+// --Batch that fits is stored with the shared TTL
+// --Batch that overflows evicts the soonest expiring entries
+//
+// -Error
+// --Rejected key stops the batch
+func TestCache_SetMany(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(3), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+	require.Nil(t, cache.Set(key("existing"), "existing", 10*time.Second))
+
+	require.Nil(t, cache.SetMany(map[key]interface{}{"a": 1, "b": 2}, 60*time.Second))
+	assertCacheHasNKeys(t, 3, cache)
+	assertKeyMapsToValue(t, 1, key("a"), cache)
+	assertKeyMapsToValue(t, 2, key("b"), cache)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[key("a")].exp)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[key("b")].exp)
+
+	require.Nil(t, cache.SetMany(map[key]interface{}{"c": 3}))
+	assertCacheHasNKeys(t, 3, cache)
+	assertKeyDoesNotExist(t, key("existing"), cache)
+	assertKeyMapsToValue(t, 3, key("c"), cache)
+	assert.Nil(t, cache.checkInvariants())
+
+	limited, err := NewTTLCache[key, interface{}](WithMaxKeyLength(5))
+	require.Nil(t, err)
+	err = limited.SetMany(map[key]interface{}{"toolong": 1})
+	assert.Equal(t, newKeyTooLongErr(7, 5), err)
+	assertCacheHasNKeys(t, 0, limited)
+}
+
+// TestCases
+// -Success
+// --Absent key is written
+// --Expired key is overwritten
+//
+// -Error
+// --Live key blocks the write and keeps its value and expiry
+func TestCache_SetNX(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	written, err := cache.SetNX(key("absent"), "first", 60*time.Second)
+	assert.Nil(t, err)
+	assert.True(t, written)
+	assertKeyMapsToValue(t, "first", key("absent"), cache)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[key("absent")].exp)
+
+	exp := cache.cache[key("absent")].exp
+	written, err = cache.SetNX(key("absent"), "second")
+	assert.Nil(t, err)
+	assert.False(t, written)
+	assertKeyMapsToValue(t, "first", key("absent"), cache)
+	assert.Equal(t, exp, cache.cache[key("absent")].exp)
+
+	require.Nil(t, cache.Set(key("expired"), "stale"))
+	cache.moveHKEntry(cache.cache[key("expired")], getExp(-time.Second))
+	written, err = cache.SetNX(key("expired"), "fresh")
+	assert.Nil(t, err)
+	assert.True(t, written)
+	assertKeyMapsToValue(t, "fresh", key("expired"), cache)
+	assertExpNear(t, getExp(30*time.Second), cache.cache[key("expired")].exp)
+	assert.Nil(t, cache.checkInvariants())
+}
+
+// TestCases
+// -Success
+// --A live entry is replaced with the new value and TTL
+// --An expired entry is not written
+// --An absent key or a cached miss is not written
+func TestCache_Replace(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	require.Nil(t, cache.Set(key("live"), "first"))
+	written, err := cache.Replace(key("live"), "second", 60*time.Second)
+	assert.Nil(t, err)
+	assert.True(t, written)
+	assertKeyMapsToValue(t, "second", key("live"), cache)
+	assertExpNear(t, getExp(60*time.Second), cache.cache[key("live")].exp)
+
+	require.Nil(t, cache.Set(key("expired"), "stale"))
+	exp := getExp(-time.Second)
+	cache.moveHKEntry(cache.cache[key("expired")], exp)
+	written, err = cache.Replace(key("expired"), "fresh")
+	assert.Nil(t, err)
+	assert.False(t, written)
+	assert.Equal(t, "stale", cache.cache[key("expired")].value)
+	assert.Equal(t, exp, cache.cache[key("expired")].exp)
+
+	written, err = cache.Replace(key("absent"), "value")
+	assert.Nil(t, err)
+	assert.False(t, written)
+	assertKeyDoesNotExist(t, key("absent"), cache)
+
+	require.Nil(t, cache.SetMissing(key("missing")))
+	written, err = cache.Replace(key("missing"), "value")
+	assert.Nil(t, err)
+	assert.False(t, written)
+	_, err = cache.Get(key("missing"))
+	assert.Equal(t, ErrCachedMiss, err)
+	assert.Nil(t, cache.checkInvariants())
+}
+
+// TestCases
+// -Success
+// --A cached miss is reported as ErrCachedMiss and takes a slot
+// --GetOrSet short-circuits on a cached miss without calling fn
+// --Has and GetMany treat a cached miss as absent
+// --An expired cached miss is a true miss again
+// --Set over a cached miss stores the value
+func TestCache_SetMissing(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	require.Nil(t, cache.SetMissing(key("missing"), time.Minute))
+	value, err := cache.Get(key("missing"))
+	assert.Equal(t, ErrCachedMiss, err)
+	assert.Nil(t, value)
+	assertCacheHasNKeys(t, 1, cache)
+	assertExpNear(t, getExp(time.Minute), cache.cache[key("missing")].exp)
+
+	calls := 0
+	_, err = cache.GetOrSet(key("missing"), func() (interface{}, error) {
+		calls++
+		return "loaded", nil
+	})
+	assert.Equal(t, ErrCachedMiss, err)
+	assert.Zero(t, calls)
+	assert.False(t, cache.Has(key("missing")))
+	values, misses := cache.GetMany([]key{"missing"})
+	assert.Empty(t, values)
+	assert.Equal(t, []key{"missing"}, misses)
+
+	cache.moveHKEntry(cache.cache[key("missing")], getExp(-time.Second))
+	_, err = cache.Get(key("missing"))
+	assert.Equal(t, newKeyNotFoundErr(key("missing")), err)
+	cache.SweepTick()
+	assertCacheHasNKeys(t, 0, cache)
+
+	require.Nil(t, cache.SetMissing(key("missing")))
+	require.Nil(t, cache.Set(key("missing"), "found"))
+	assertKeyMapsToValue(t, "found", key("missing"), cache)
+	assert.Nil(t, cache.checkInvariants())
+}
+
+// TestCases
+// -Success
+// --SetNX and GetOrStore write over a cached miss
+// --GetFirst skips a cached miss
+// --Pop and PopMany remove a cached miss without returning a value
+// --CopyKey copies a cached miss as a cached miss
+// --Compute is told a cached miss was not found
+//
+// -Error
+// --Update, Migrate, SwapKeys and DeleteIf return ErrCachedMiss and leave the tombstone alone
+func TestCache_SetMissing_OtherPaths(t *testing.T) {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(t, err)
+
+	require.Nil(t, cache.SetMissing(key("nx")))
+	written, err := cache.SetNX(key("nx"), "value")
+	assert.Nil(t, err)
+	assert.True(t, written)
+	assertKeyMapsToValue(t, "value", key("nx"), cache)
+
+	require.Nil(t, cache.SetMissing(key("store")))
+	value, loaded := cache.GetOrStore(key("store"), "default")
+	assert.False(t, loaded)
+	assert.Equal(t, "default", value)
+	assertKeyMapsToValue(t, "default", key("store"), cache)
+
+	require.Nil(t, cache.SetMissing(key("first")))
+	k, value, err := cache.GetFirst(key("first"), key("nx"))
+	assert.Nil(t, err)
+	assert.Equal(t, key("nx"), k)
+	assert.Equal(t, "value", value)
+
+	require.Nil(t, cache.SetMissing(key("pop")))
+	_, err = cache.Pop(key("pop"))
+	assert.Equal(t, ErrCachedMiss, err)
+	assertKeyDoesNotExist(t, key("pop"), cache)
+	require.Nil(t, cache.SetMissing(key("pop")))
+	values, missing := cache.PopMany([]key{"pop", "nx"})
+	assert.Equal(t, map[key]interface{}{"nx": "value"}, values)
+	assert.Equal(t, []key{"pop"}, missing)
+	assertKeyDoesNotExist(t, key("pop"), cache)
+
+	require.Nil(t, cache.SetMissing(key("src")))
+	require.Nil(t, cache.CopyKey(key("src"), key("dst")))
+	_, err = cache.Get(key("dst"))
+	assert.Equal(t, ErrCachedMiss, err)
+
+	var computeFound bool
+	require.Nil(t, cache.Compute(key("src"), func(old interface{}, found bool) (interface{}, time.Duration, bool) {
+		computeFound = found
+		return "computed", 0, true
+	}))
+	assert.False(t, computeFound)
+	assertKeyMapsToValue(t, "computed", key("src"), cache)
+
+	require.Nil(t, cache.SetMissing(key("tombstone")))
+	assert.Equal(t, ErrCachedMiss, cache.Update(key("tombstone"), "value"))
+	assert.Equal(t, ErrCachedMiss, cache.Migrate(key("tombstone"), func(old interface{}) (interface{}, bool, error) {
+		return "value", true, nil
+	}))
+	assert.Equal(t, ErrCachedMiss, cache.SwapKeys(key("store"), key("tombstone")))
+	_, err = cache.DeleteIf(key("tombstone"), func(interface{}) bool { return true })
+	assert.Equal(t, ErrCachedMiss, err)
+	_, err = cache.Get(key("tombstone"))
+	assert.Equal(t, ErrCachedMiss, err)
+	assertKeyMapsToValue(t, "default", key("store"), cache)
+	assert.Nil(t, cache.checkInvariants())
+}
+
 func TestCache_UpdateCache(t *testing.T) {
 	uc := new(updateCacheSuite)
 	suite.Run(t, uc)
 }
 
 type updateCacheSuite struct {
-	e1 *cacheEntry
-	e2 *cacheEntry
+	e1 *cacheEntry[key, interface{}]
+	e2 *cacheEntry[key, interface{}]
 	cacheSuite
 }
 
@@ -274,7 +964,7 @@ func (uc *updateCacheSuite) SetupTest() {
 	uc.cacheSuite.SetupSuite()
 
 	//Add two entries to cache
-	uc.e1 = &cacheEntry{
+	uc.e1 = &cacheEntry[key, interface{}]{
 		key:   key("key1"),
 		value: "initialValue",
 		exp:   12345,
@@ -282,7 +972,7 @@ func (uc *updateCacheSuite) SetupTest() {
 	uc.cache.cache[uc.e1.key] = uc.e1
 	uc.cache.insertNewHKEntry(uc.e1)
 
-	uc.e2 = &cacheEntry{
+	uc.e2 = &cacheEntry[key, interface{}]{
 		key:   key("key2"),
 		value: "initialValue",
 		exp:   23456,
@@ -299,7 +989,7 @@ func (uc *updateCacheSuite) SetupTest() {
 
 func (uc *updateCacheSuite) TestUpdateCache_Success() {
 	//update entry `e1`
-	updateEntry := &cacheEntry{
+	updateEntry := &cacheEntry[key, interface{}]{
 		key:   uc.e1.key,
 		value: 52,
 		exp:   67890,
@@ -316,8 +1006,55 @@ func (uc *updateCacheSuite) TestUpdateCache_Success() {
 	assert.Equal(uc.T(), uc.e2, uc.cache.ttlHK[0])
 }
 
+func (uc *updateCacheSuite) TestUpdateCache_KeepsOrder() {
+	e3 := &cacheEntry[key, interface{}]{
+		key:   key("key3"),
+		value: "initialValue",
+		exp:   34567,
+	}
+	uc.cache.cache[e3.key] = e3
+	uc.cache.insertNewHKEntry(e3)
+
+	//move `e1` between `e2` and `e3`
+	updateEntry := &cacheEntry[key, interface{}]{
+		key:   uc.e1.key,
+		value: 52,
+		exp:   30000,
+	}
+
+	err := uc.cache.updateCacheEntry(updateEntry)
+	assert.Nil(uc.T(), err)
+	assert.Equal(uc.T(), []*cacheEntry[key, interface{}]{uc.e2, uc.e1, e3}, uc.cache.ttlHK)
+	assert.Nil(uc.T(), uc.cache.checkInvariants())
+}
+
+func (uc *updateCacheSuite) TestUpdateCache_SharedExp() {
+	//Give `e2` and `e3` the same exp as `e1`, behind it in ttlHK
+	e3 := &cacheEntry[key, interface{}]{
+		key:   key("key3"),
+		value: "initialValue",
+		exp:   uc.e1.exp,
+	}
+	uc.cache.cache[e3.key] = e3
+	uc.cache.moveHKEntry(uc.e2, uc.e1.exp)
+	uc.cache.insertNewHKEntry(e3)
+
+	updateEntry := &cacheEntry[key, interface{}]{
+		key:   e3.key,
+		value: 52,
+		exp:   67890,
+	}
+
+	err := uc.cache.updateCacheEntry(updateEntry)
+	assert.Nil(uc.T(), err)
+	assert.Equal(uc.T(), 3, len(uc.cache.ttlHK))
+	assert.Equal(uc.T(), e3, uc.cache.ttlHK[2])
+	assert.ElementsMatch(uc.T(), []*cacheEntry[key, interface{}]{uc.e1, uc.e2}, uc.cache.ttlHK[:2])
+	assert.Nil(uc.T(), uc.cache.checkInvariants())
+}
+
 func (uc *updateCacheSuite) TestUpdateCache_InvalidRequest() {
-	updateEntry := &cacheEntry{
+	updateEntry := &cacheEntry[key, interface{}]{
 		key:   key("invalid key"),
 		value: 23,
 		exp:   getExp(uc.cache.defaultTTL),
@@ -328,12 +1065,173 @@ func (uc *updateCacheSuite) TestUpdateCache_InvalidRequest() {
 	assert.Equal(uc.T(), newBadUpdateRequestErr(updateEntry.key), err)
 }
 
-//TestCases
-//-Success
-//--Successfully found
+// BenchmarkUpdateCacheEntry compares re-sorting all of ttlHK on every update, as updateCacheEntry used to,
+// with moving only the updated entry.
+func BenchmarkUpdateCacheEntry(b *testing.B) {
+	for _, n := range []int{100, 10000} {
+		newCache := func(b *testing.B) (*TTLCache[key, interface{}], []key) {
+			cache, err := NewTTLCache[key, interface{}](WithSize(uint(n)))
+			require.Nil(b, err)
+			keys := make([]key, n)
+			for i := range keys {
+				keys[i] = key(fmt.Sprintf("key%d", i))
+				require.Nil(b, cache.Set(keys[i], i, time.Duration(i+1)*time.Second))
+			}
+			return cache, keys
+		}
+
+		b.Run(fmt.Sprintf("resort/%d", n), func(b *testing.B) {
+			cache, keys := newCache(b)
+			defer cache.Close()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				entry := cache.cache[keys[i%n]]
+				entry.exp = getExp(time.Duration(n+i) * time.Second)
+				sort.Slice(cache.ttlHK, func(x, y int) bool {
+					return cache.ttlHK[x].exp < cache.ttlHK[y].exp
+				})
+			}
+		})
+
+		b.Run(fmt.Sprintf("reinsert/%d", n), func(b *testing.B) {
+			cache, keys := newCache(b)
+			defer cache.Close()
+			update := &cacheEntry[key, interface{}]{}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				update.key = keys[i%n]
+				update.exp = getExp(time.Duration(n+i) * time.Second)
+				_ = cache.updateCacheEntry(update)
+			}
+		})
+	}
+}
+
+// TestCases
+// -Success
+// --Removes the entry from the map and ttlHK, keeping ttlHK sorted
+// --Entries sharing an exp remove the right one
+// --Subscribers are notified
+// --Pop returns the value and removes the entry
+// --Clear removes every entry and keeps ttlHK's capacity
 //
-//-Error
-//--Not found
+// -Error
+// --Not found
+// --Second Pop of the same key misses
+// --Pop of an expired entry misses
+func TestCache_Delete(t *testing.T) {
+	dc := new(deleteCacheSuite)
+	suite.Run(t, dc)
+}
+
+type deleteCacheSuite struct {
+	cacheSuite
+}
+
+func (dc *deleteCacheSuite) SetupTest() {
+	dc.cacheSuite.SetupSuite()
+	for i, k := range []key{"a", "b", "c", "d"} {
+		require.Nil(dc.T(), dc.cache.Set(k, string(k), time.Duration(i+1)*10*time.Second))
+	}
+}
+
+func (dc *deleteCacheSuite) TestCache_Delete_Success() {
+	err := dc.cache.Delete(key("b"))
+	assert.Nil(dc.T(), err)
+
+	assertCacheHasNKeys(dc.T(), 3, dc.cache)
+	assertKeyDoesNotExist(dc.T(), key("b"), dc.cache)
+	assert.Equal(dc.T(), []key{"a", "c", "d"}, entryKeys(dc.cache.EntriesSnapshot()))
+	assert.Nil(dc.T(), dc.cache.checkInvariants())
+}
+
+func (dc *deleteCacheSuite) TestCache_Delete_SharedExp() {
+	require.Nil(dc.T(), dc.cache.Set(key("c2"), "c2"))
+	dc.cache.moveHKEntry(dc.cache.cache[key("c2")], dc.cache.cache[key("c")].exp)
+
+	err := dc.cache.Delete(key("c2"))
+	assert.Nil(dc.T(), err)
+
+	assertCacheHasNKeys(dc.T(), 4, dc.cache)
+	assertKeyMapsToValue(dc.T(), "c", key("c"), dc.cache)
+	assert.Nil(dc.T(), dc.cache.checkInvariants())
+}
+
+func (dc *deleteCacheSuite) TestCache_Delete_Notifies() {
+	events, unsubscribe := dc.cache.Subscribe(key("a"), 1)
+	defer unsubscribe()
+
+	require.Nil(dc.T(), dc.cache.Delete(key("a")))
+	assert.Equal(dc.T(), ChangeEvent[interface{}]{Type: ChangeDelete, Value: "a"}, <-events)
+}
+
+func (dc *deleteCacheSuite) TestCache_Pop() {
+	value, err := dc.cache.Pop(key("b"))
+	assert.Nil(dc.T(), err)
+	assert.Equal(dc.T(), "b", value)
+	assertCacheHasNKeys(dc.T(), 3, dc.cache)
+	assertKeyDoesNotExist(dc.T(), key("b"), dc.cache)
+	assert.Nil(dc.T(), dc.cache.checkInvariants())
+
+	value, err = dc.cache.Pop(key("b"))
+	assert.Nil(dc.T(), value)
+	assert.Equal(dc.T(), newKeyNotFoundErr(key("b")), err)
+}
+
+func (dc *deleteCacheSuite) TestCache_Pop_Expired() {
+	dc.cache.moveHKEntry(dc.cache.cache[key("a")], getExp(-time.Second))
+
+	value, err := dc.cache.Pop(key("a"))
+	assert.Nil(dc.T(), value)
+	assert.Equal(dc.T(), newKeyNotFoundErr(key("a")), err)
+}
+
+func (dc *deleteCacheSuite) TestCache_Clear() {
+	events, unsubscribe := dc.cache.Subscribe(key("a"), 1)
+	defer unsubscribe()
+	capacity := cap(dc.cache.ttlHK)
+
+	dc.cache.Clear()
+
+	assert.Equal(dc.T(), 0, dc.cache.Len())
+	assertCacheHasNKeys(dc.T(), 0, dc.cache)
+	assert.Equal(dc.T(), capacity, cap(dc.cache.ttlHK))
+	assert.Equal(dc.T(), ChangeEvent[interface{}]{Type: ChangeDelete, Value: "a"}, <-events)
+
+	value, err := dc.cache.Get(key("a"))
+	assert.Nil(dc.T(), value)
+	assert.Equal(dc.T(), newKeyNotFoundErr(key("a")), err)
+
+	require.Nil(dc.T(), dc.cache.Set(key("after"), "after"))
+	assertKeyMapsToValue(dc.T(), "after", key("after"), dc.cache)
+	assert.Nil(dc.T(), dc.cache.checkInvariants())
+}
+
+func (dc *deleteCacheSuite) TestCache_Delete_NotFound() {
+	err := dc.cache.Delete(key("missing"))
+	assert.Equal(dc.T(), newKeyNotFoundErr(key("missing")), err)
+	assertCacheHasNKeys(dc.T(), 4, dc.cache)
+}
+
+// TestCases
+// -Success
+// --Successfully found
+// --GetOrStore returns an existing value
+// --GetOrStore stores the default on a miss
+// --GetFirst returns the first present key in order
+// --GetMany returns hits and reports misses, counting expired entries as misses
+// --Entry expiring this instant is still live
+// --Peek returns a live value
+// --Has reports live keys without sliding their TTL
+// --GetWithExpiry returns the expiry the Set's TTL gave
+//
+// -Error
+// --Not found
+// --GetWithExpiry misses on an expired entry
+// --Expired entry is a miss and is left for the sweep
+// --Expired entry is removed WithCleanupOnGet
+// --Peek on an expired entry is a miss and never removes it
+// --GetFirst with no present keys
 func TestCache_Get(t *testing.T) {
 	gc := new(getCacheSuite)
 	suite.Run(t, gc)
@@ -342,7 +1240,7 @@ func TestCache_Get(t *testing.T) {
 type getCacheSuite struct {
 	key   key
 	value interface{}
-	entry *cacheEntry
+	entry *cacheEntry[key, interface{}]
 	cacheSuite
 }
 
@@ -350,7 +1248,7 @@ func (gc *getCacheSuite) SetupSuite() {
 	gc.cacheSuite.SetupSuite()
 	gc.key = key("exists")
 	gc.value = "value"
-	gc.entry = newCacheEntry(gc.key, gc.value, getExp(gc.defaultTTL))
+	gc.entry = newCacheEntry[key, interface{}](gc.key, gc.value, getExp(gc.defaultTTL))
 	gc.cache.cache[gc.key] = gc.entry
 }
 
@@ -360,6 +1258,178 @@ func (gc *getCacheSuite) TestCache_Get_Success() {
 	assert.Equal(gc.T(), gc.value, value)
 }
 
+func (gc *getCacheSuite) TestCache_GetOrStore_Existing() {
+	value, loaded := gc.cache.GetOrStore(gc.key, "default")
+	assert.True(gc.T(), loaded)
+	assert.Equal(gc.T(), gc.value, value)
+	assertKeyMapsToValue(gc.T(), gc.value, gc.key, gc.cache)
+}
+
+func (gc *getCacheSuite) TestCache_GetOrStore_StoresDefault() {
+	k := key("stored default")
+	value, loaded := gc.cache.GetOrStore(k, "default", 60*time.Second)
+	assert.False(gc.T(), loaded)
+	assert.Equal(gc.T(), "default", value)
+	assertKeyMapsToValue(gc.T(), "default", k, gc.cache)
+	assertExpNear(gc.T(), getExp(60*time.Second), gc.cache.cache[k].exp)
+
+	value, loaded = gc.cache.GetOrStore(k, "other default")
+	assert.True(gc.T(), loaded)
+	assert.Equal(gc.T(), "default", value)
+}
+
+func (gc *getCacheSuite) TestCache_GetFirst() {
+	specific := key("tenant:user")
+	general := key("tenant")
+	require.Nil(gc.T(), gc.cache.Set(general, "general"))
+
+	matched, value, err := gc.cache.GetFirst(specific, general)
+	assert.Nil(gc.T(), err)
+	assert.Equal(gc.T(), general, matched)
+	assert.Equal(gc.T(), "general", value)
+
+	require.Nil(gc.T(), gc.cache.Set(specific, "specific"))
+	matched, value, err = gc.cache.GetFirst(specific, general)
+	assert.Nil(gc.T(), err)
+	assert.Equal(gc.T(), specific, matched)
+	assert.Equal(gc.T(), "specific", value)
+}
+
+func (gc *getCacheSuite) TestCache_GetMany() {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(gc.T(), err)
+	require.Nil(gc.T(), cache.Set(key("a"), "a"))
+	require.Nil(gc.T(), cache.Set(key("b"), "b"))
+	require.Nil(gc.T(), cache.Set(key("expired"), "expired"))
+	cache.moveHKEntry(cache.cache[key("expired")], getExp(-time.Second))
+
+	values, missing := cache.GetMany([]key{"a", "missing", "b", "expired"})
+	assert.Equal(gc.T(), map[key]interface{}{"a": "a", "b": "b"}, values)
+	assert.Equal(gc.T(), []key{"missing", "expired"}, missing)
+	assert.Equal(gc.T(), Stats{Hits: 2, Misses: 2, Sets: 3}, cache.Stats())
+}
+
+func (gc *getCacheSuite) TestCache_GetFirst_NoneFound() {
+	keys := []key{"missing1", "missing2"}
+	matched, value, err := gc.cache.GetFirst(keys...)
+	assert.Equal(gc.T(), key(""), matched)
+	assert.Nil(gc.T(), value)
+	assert.Equal(gc.T(), newNoKeysFoundErr(keys), err)
+}
+
+func (gc *getCacheSuite) TestCache_Get_Expired() {
+	k := key("expired")
+	require.Nil(gc.T(), gc.cache.Set(k, "value", time.Second))
+	//Move the entry's expiry into the past as if its TTL had elapsed
+	entry := gc.cache.cache[k]
+	gc.cache.moveHKEntry(entry, getExp(-time.Second))
+
+	value, err := gc.cache.Get(k)
+	assert.Nil(gc.T(), value)
+	assert.Equal(gc.T(), newKeyNotFoundErr(k), err)
+
+	//Ensure the expired entry is left for the sweep
+	assert.Equal(gc.T(), entry, gc.cache.cache[k])
+	assert.NotEqual(gc.T(), -1, gc.cache.indexOfHKEntry(entry))
+}
+
+func (gc *getCacheSuite) TestCache_Get_CleanupOnGet() {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithCleanupOnGet())
+	require.Nil(gc.T(), err)
+	k := key("expired")
+	require.Nil(gc.T(), cache.Set(k, "value", time.Second))
+	entry := cache.cache[k]
+	cache.moveHKEntry(entry, getExp(-time.Second))
+	events, unsubscribe := cache.Subscribe(k, 1)
+	defer unsubscribe()
+
+	value, err := cache.Get(k)
+	assert.Nil(gc.T(), value)
+	assert.Equal(gc.T(), newKeyNotFoundErr(k), err)
+
+	//Ensure the expired entry was dropped from both structures
+	_, exists := cache.cache[k]
+	assert.False(gc.T(), exists)
+	assert.Equal(gc.T(), -1, cache.indexOfHKEntry(entry))
+	assert.Equal(gc.T(), ChangeEvent[interface{}]{Type: ChangeExpire, Value: "value"}, <-events)
+}
+
+func (gc *getCacheSuite) TestCache_Peek() {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second), WithCleanupOnGet())
+	require.Nil(gc.T(), err)
+	require.Nil(gc.T(), cache.Set(key("live"), "value"))
+	require.Nil(gc.T(), cache.Set(key("expired"), "value"))
+	entry := cache.cache[key("expired")]
+	cache.moveHKEntry(entry, getExp(-time.Second))
+
+	value, err := cache.Peek(key("live"))
+	assert.Nil(gc.T(), err)
+	assert.Equal(gc.T(), "value", value)
+
+	value, err = cache.Peek(key("expired"))
+	assert.Nil(gc.T(), value)
+	assert.Equal(gc.T(), newKeyNotFoundErr(key("expired")), err)
+	assert.Equal(gc.T(), entry, cache.cache[key("expired")])
+}
+
+func (gc *getCacheSuite) TestCache_Has() {
+	cache, err := NewTTLCache[key, interface{}](WithDefaultTTL(30*time.Second), WithSlidingTTL())
+	require.Nil(gc.T(), err)
+	require.Nil(gc.T(), cache.Set(key("live"), "value", 10*time.Second))
+	require.Nil(gc.T(), cache.Set(key("expired"), "value"))
+	cache.moveHKEntry(cache.cache[key("expired")], getExp(-time.Second))
+	exp := cache.cache[key("live")].exp
+
+	assert.True(gc.T(), cache.Has(key("live")))
+	assert.Equal(gc.T(), exp, cache.cache[key("live")].exp)
+	assert.False(gc.T(), cache.Has(key("expired")))
+	assert.False(gc.T(), cache.Has(key("absent")))
+}
+
+func (gc *getCacheSuite) TestCache_GetWithExpiry() {
+	cache, err := NewTTLCache[key, interface{}](WithDefaultTTL(30 * time.Second))
+	require.Nil(gc.T(), err)
+	before := time.Now()
+	require.Nil(gc.T(), cache.Set(key("live"), "value", 45*time.Second))
+	after := time.Now()
+	require.Nil(gc.T(), cache.Set(key("pinned"), "value", NoExpiry))
+	require.Nil(gc.T(), cache.Set(key("expired"), "value"))
+	cache.moveHKEntry(cache.cache[key("expired")], getExp(-time.Second))
+
+	value, expiresAt, err := cache.GetWithExpiry(key("live"))
+	assert.Nil(gc.T(), err)
+	assert.Equal(gc.T(), "value", value)
+	assert.False(gc.T(), expiresAt.Before(before.Add(45*time.Second)))
+	assert.False(gc.T(), expiresAt.After(after.Add(45*time.Second)))
+	assert.Equal(gc.T(), cache.cache[key("live")].exp, expiresAt.UnixNano())
+
+	value, expiresAt, err = cache.GetWithExpiry(key("pinned"))
+	assert.Nil(gc.T(), err)
+	assert.Equal(gc.T(), "value", value)
+	assert.True(gc.T(), expiresAt.IsZero())
+
+	value, expiresAt, err = cache.GetWithExpiry(key("expired"))
+	assert.Equal(gc.T(), newKeyNotFoundErr(key("expired")), err)
+	assert.Nil(gc.T(), value)
+	assert.True(gc.T(), expiresAt.IsZero())
+}
+
+func (gc *getCacheSuite) TestCache_Get_ExpiresNow() {
+	cache, err := NewTTLCache[key, interface{}](WithSize(10), WithDefaultTTL(30*time.Second), WithSweepPeriod(5*time.Second))
+	require.Nil(gc.T(), err)
+	now := time.Now()
+	cache.now = func() time.Time {
+		return now
+	}
+	k := key("expires now")
+	require.Nil(gc.T(), cache.Set(k, "value"))
+	cache.moveHKEntry(cache.cache[k], now.UnixNano())
+
+	value, err := cache.Get(k)
+	assert.Nil(gc.T(), err)
+	assert.Equal(gc.T(), "value", value)
+}
+
 func (gc *getCacheSuite) TestCache_Get_NotFound() {
 	nonexistentKey := key("doesn't exist")
 	value, err := gc.cache.Get(nonexistentKey)
@@ -368,13 +1438,17 @@ func (gc *getCacheSuite) TestCache_Get_NotFound() {
 	assert.Equal(gc.T(), newKeyNotFoundErr(nonexistentKey), err)
 }
 
-//Eviction
-//TestCases
-//-Success
-//--Empty Cache
-//--Nothing to evict
-//--Several things to evict
-//--All things to evict
+// Eviction
+// TestCases
+// -Success
+// --Empty Cache
+// --Nothing to evict
+// --Several things to evict
+// --All things to evict
+// --Out of order ttlHK healed only under WithSelfHealing
+// --Normalize reconciles the map and ttlHK
+// --SweepTick reaps expired entries
+// --TriggerSweep reports how many entries it reaped
 func TestCache_evict(t *testing.T) {
 	ec := new(evictCacheSuite)
 	suite.Run(t, ec)
@@ -394,7 +1468,7 @@ func (ec *evictCacheSuite) TestCache_Evict_EmptyCache() {
 	assert.Equal(ec.T(), expectedLen, len(ec.cache.ttlHK))
 
 	assert.NotPanics(ec.T(), func() {
-		ec.cache.evict(uint32(time.Now().Unix()))
+		ec.cache.evict(time.Now().UnixNano())
 	})
 
 	assert.Equal(ec.T(), expectedLen, len(ec.cache.cache))
@@ -413,7 +1487,7 @@ func (ec *evictCacheSuite) TestCache_Evict_NothingToEvict() {
 	assert.Equal(ec.T(), expectedLen, len(ec.cache.ttlHK))
 
 	assert.NotPanics(ec.T(), func() {
-		ec.cache.evict(uint32(time.Now().Unix()))
+		ec.cache.evict(time.Now().UnixNano())
 	})
 
 	assert.Equal(ec.T(), expectedLen, len(ec.cache.cache))
@@ -432,8 +1506,8 @@ func (ec *evictCacheSuite) TestCache_Evict_SeveralThingsToEvict() {
 	keyToEvict1 := key("ek1")
 	keyToEvict2 := key("ek2")
 
-	evict1 := newCacheEntry(keyToEvict1, "value to evict1", uint32(time.Now().Add(-5*time.Second).Unix()))
-	evict2 := newCacheEntry(keyToEvict2, "value to evict2", uint32(time.Now().Add(-1*time.Second).Unix()))
+	evict1 := newCacheEntry[key, interface{}](keyToEvict1, "value to evict1", time.Now().Add(-5*time.Second).UnixNano())
+	evict2 := newCacheEntry[key, interface{}](keyToEvict2, "value to evict2", time.Now().Add(-1*time.Second).UnixNano())
 
 	ec.cache.cache[keyToEvict1] = evict1
 	ec.cache.insertNewHKEntry(evict1)
@@ -445,7 +1519,7 @@ func (ec *evictCacheSuite) TestCache_Evict_SeveralThingsToEvict() {
 	assertCacheHasNKeys(ec.T(), expectedLen, ec.cache)
 
 	assert.NotPanics(ec.T(), func() {
-		ec.cache.evict(uint32(time.Now().Unix()))
+		ec.cache.evict(time.Now().UnixNano())
 	})
 
 	expectedLen = 1
@@ -464,8 +1538,8 @@ func (ec *evictCacheSuite) TestCache_Evict_AllTheThings() {
 	keyToEvict1 := key("ek1")
 	keyToEvict2 := key("ek2")
 
-	evict1 := newCacheEntry(keyToEvict1, "value to evict1", uint32(time.Now().Add(-5*time.Second).Unix()))
-	evict2 := newCacheEntry(keyToEvict2, "value to evict2", uint32(time.Now().Add(-1*time.Second).Unix()))
+	evict1 := newCacheEntry[key, interface{}](keyToEvict1, "value to evict1", time.Now().Add(-5*time.Second).UnixNano())
+	evict2 := newCacheEntry[key, interface{}](keyToEvict2, "value to evict2", time.Now().Add(-1*time.Second).UnixNano())
 
 	ec.cache.cache[keyToEvict1] = evict1
 	ec.cache.insertNewHKEntry(evict1)
@@ -478,7 +1552,7 @@ func (ec *evictCacheSuite) TestCache_Evict_AllTheThings() {
 	assertCacheHasNKeys(ec.T(), expectedLen, ec.cache)
 
 	assert.NotPanics(ec.T(), func() {
-		ec.cache.evict(uint32(time.Now().Unix()))
+		ec.cache.evict(time.Now().UnixNano())
 	})
 
 	expectedLen = 0
@@ -490,34 +1564,135 @@ func (ec *evictCacheSuite) TestCache_Evict_AllTheThings() {
 	assertKeyDoesNotExist(ec.T(), keyToEvict2, ec.cache)
 }
 
-//prospective: Export Manual Eviction
+func (ec *evictCacheSuite) TestCache_Evict_SelfHealing() {
+	for _, selfHealing := range []bool{false, true} {
+		opts := []Option{WithSize(10), WithDefaultTTL(30 * time.Second), WithSweepPeriod(5 * time.Second)}
+		if selfHealing {
+			opts = append(opts, WithSelfHealing())
+		}
+		cache, err := NewTTLCache[key, interface{}](opts...)
+		require.Nil(ec.T(), err)
+
+		require.Nil(ec.T(), cache.Set(key("live"), "value"))
+		expired := newCacheEntry[key, interface{}](key("expired"), "value", time.Now().Add(-5*time.Second).UnixNano())
+		cache.cache[expired.key] = expired
+		//Corrupt ttlHK by appending the expired entry behind a live one
+		cache.ttlHK = append(cache.ttlHK, expired)
+		require.NotNil(ec.T(), cache.checkInvariants())
+
+		cache.evict(time.Now().UnixNano())
+
+		if !selfHealing {
+			assertCacheHasNKeys(ec.T(), 2, cache)
+			continue
+		}
+		assertCacheHasNKeys(ec.T(), 1, cache)
+		assertKeyDoesNotExist(ec.T(), expired.key, cache)
+		assert.Nil(ec.T(), cache.checkInvariants())
+	}
+}
+
+func (ec *evictCacheSuite) TestCache_Normalize() {
+	require.Nil(ec.T(), ec.cache.Set(key("late"), "value", 20*time.Second))
+	require.Nil(ec.T(), ec.cache.Set(key("early"), "value", 10*time.Second))
+
+	//Entry only in the map
+	unindexed := newCacheEntry[key, interface{}](key("unindexed"), "value", getExp(15*time.Second))
+	ec.cache.cache[unindexed.key] = unindexed
+	//Entry only in ttlHK, plus a duplicate, appended out of order
+	orphan := newCacheEntry[key, interface{}](key("orphan"), "value", getExp(5*time.Second))
+	ec.cache.ttlHK = append(ec.cache.ttlHK, orphan, ec.cache.cache[key("early")])
+	require.NotNil(ec.T(), ec.cache.checkInvariants())
+
+	ec.cache.Normalize()
 
-func assertCachesAreEqual(t *testing.T, expected, actual *TTLCache) {
+	assert.Nil(ec.T(), ec.cache.checkInvariants())
+	assertCacheHasNKeys(ec.T(), 3, ec.cache)
+	assert.Equal(ec.T(), []key{"early", "unindexed", "late"}, entryKeys(ec.cache.EntriesSnapshot()))
+}
+
+func (ec *evictCacheSuite) TestCache_SweepTick() {
+	k := key("not expired")
+	require.Nil(ec.T(), ec.cache.Set(k, "value", 5*time.Second))
+
+	expired := newCacheEntry[key, interface{}](key("expired"), "value", time.Now().Add(-5*time.Second).UnixNano())
+	ec.cache.cache[expired.key] = expired
+	ec.cache.insertNewHKEntry(expired)
+	assertCacheHasNKeys(ec.T(), 2, ec.cache)
+
+	ec.cache.SweepTick()
+
+	assertCacheHasNKeys(ec.T(), 1, ec.cache)
+	assertKeyDoesNotExist(ec.T(), expired.key, ec.cache)
+	assertKeyMapsToValue(ec.T(), "value", k, ec.cache)
+}
+
+func (ec *evictCacheSuite) TestCache_TriggerSweep() {
+	assert.Equal(ec.T(), 0, ec.cache.TriggerSweep())
+
+	require.Nil(ec.T(), ec.cache.Set(key("live"), "value", 5*time.Second))
+	for _, k := range []key{"expired1", "expired2", "expired3"} {
+		require.Nil(ec.T(), ec.cache.Set(k, "value"))
+		ec.cache.moveHKEntry(ec.cache.cache[k], getExp(-time.Second))
+	}
+	//Len already skips expired entries; the map still holds them until the sweep
+	assertCacheHasNKeys(ec.T(), 4, ec.cache)
+	assert.Equal(ec.T(), 1, ec.cache.Len())
+
+	assert.Equal(ec.T(), 3, ec.cache.TriggerSweep())
+	assertCacheHasNKeys(ec.T(), 1, ec.cache)
+	assert.Equal(ec.T(), 1, ec.cache.Len())
+	assertKeyMapsToValue(ec.T(), "value", key("live"), ec.cache)
+	assert.Equal(ec.T(), 0, ec.cache.TriggerSweep())
+}
+
+// getExp is the exp of an entry stored now with ttl in a cache reading the wall clock.
+func getExp(ttl time.Duration) int64 {
+	return time.Now().Add(ttl).UnixNano()
+}
+
+func assertCachesAreEqual(t *testing.T, expected, actual *TTLCache[key, interface{}]) {
 	if expected == nil || actual == nil {
 		assert.Equal(t, expected, actual)
 		return
 	}
 	//assert.Equal(t, expected.sweepTicker, actual.sweepTicker)
+	assert.Equal(t, expected.size, actual.size)
 	assert.Equal(t, expected.defaultTTL, actual.defaultTTL)
+	assert.Equal(t, expected.sweepPeriod, actual.sweepPeriod)
 	assert.Equal(t, len(expected.cache), len(actual.cache))
 	assert.Equal(t, len(expected.ttlHK), len(actual.ttlHK))
 	assert.Equal(t, cap(expected.ttlHK), cap(actual.ttlHK))
 }
 
-func assertCacheHasNKeys(t *testing.T, expectedKeys int, cache *TTLCache) {
+func assertEntriesMatch(t *testing.T, expected, actual *cacheEntry[key, interface{}]) {
+	if !assert.NotNil(t, actual) {
+		return
+	}
+	assert.Equal(t, expected.key, actual.key)
+	assert.Equal(t, expected.value, actual.value)
+	assertExpNear(t, expected.exp, actual.exp)
+}
+
+// assertExpNear asserts actual is within a second of expected, allowing for the time a test takes to run.
+func assertExpNear(t *testing.T, expected, actual int64) {
+	assert.InDelta(t, expected, actual, float64(time.Second))
+}
+
+func assertCacheHasNKeys(t *testing.T, expectedKeys int, cache *TTLCache[key, interface{}]) {
 	assert.Len(t, cache.cache, expectedKeys)
 	assert.Len(t, cache.ttlHK, expectedKeys)
 }
 
-func assertKeyDoesNotExist(t *testing.T, key key, cache *TTLCache) {
+func assertKeyDoesNotExist(t *testing.T, key key, cache *TTLCache[key, interface{}]) {
 	value, err := cache.Get(key)
 	assert.Nil(t, value)
 	assert.NotNil(t, err)
 	assert.Equal(t, newKeyNotFoundErr(key), err)
 }
 
-func assertKeyMapsToValue(t *testing.T, expectedValue interface{}, key key, cache *TTLCache) {
+func assertKeyMapsToValue(t *testing.T, expectedValue interface{}, key key, cache *TTLCache[key, interface{}]) {
 	value, err := cache.Get(key)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedValue, value)
-}
\ No newline at end of file
+}